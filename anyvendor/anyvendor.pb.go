@@ -22,6 +22,31 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type LineEndings_Style int32
+
+const (
+	LineEndings_LF   LineEndings_Style = 0
+	LineEndings_CRLF LineEndings_Style = 1
+)
+
+var LineEndings_Style_name = map[int32]string{
+	0: "LF",
+	1: "CRLF",
+}
+
+var LineEndings_Style_value = map[string]int32{
+	"LF":   0,
+	"CRLF": 1,
+}
+
+func (x LineEndings_Style) String() string {
+	return proto.EnumName(LineEndings_Style_name, int32(x))
+}
+
+func (LineEndings_Style) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{16, 0}
+}
+
 // Config object used for running anyvendor. The top level config consists of 2 main sections.
 //
 // Local is a set of matchers will be taken directly from the local module, and vendored in.
@@ -30,11 +55,59 @@ type Config struct {
 	// files to be vendored from current repo
 	Local *Local `protobuf:"bytes,1,opt,name=local,proto3" json:"local,omitempty"`
 	// list of external imports to be vendored in
-	Imports              []*Import        `protobuf:"bytes,2,rep,name=imports,proto3" json:"imports,omitempty"`
-	Settings             *FactorySettings `protobuf:"bytes,3,opt,name=settings,proto3" json:"settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Imports []*Import `protobuf:"bytes,2,rep,name=imports,proto3" json:"imports,omitempty"`
+	//
+	//settings of the manager of the config when it is run as part of a workspace, the cwd is relative to the config
+	//file. The policy is enforced by any manager ensuring the config, along with the policy of the manager.
+	Settings *FactorySettings `protobuf:"bytes,3,opt,name=settings,proto3" json:"settings,omitempty"`
+	//
+	//When enabled, the imports of every vendored proto file are followed, and the imported files are
+	//vendored in from the local module or the go mod imports until the closure is complete.
+	//Well known imports (google/protobuf/*) are resolved like any other import, unless skip_well_known_imports
+	//is set. Imports which cannot be found in any source are reported as an error.
+	ResolveImports bool `protobuf:"varint,4,opt,name=resolve_imports,json=resolveImports,proto3" json:"resolve_imports,omitempty"`
+	//
+	//When set, every import of every vendored and local proto file is checked after vendoring, and
+	//must resolve to exactly one file in the include roots.
+	ValidateImports *ImportValidation `protobuf:"bytes,5,opt,name=validate_imports,json=validateImports,proto3" json:"validate_imports,omitempty"`
+	// patches applied to the vendored files once they have been copied
+	Patch *Patch `protobuf:"bytes,6,opt,name=patch,proto3" json:"patch,omitempty"`
+	// when set, every vendored and local proto file is compiled into a FileDescriptorSet once vendoring is done
+	DescriptorSet *DescriptorSet `protobuf:"bytes,7,opt,name=descriptor_set,json=descriptorSet,proto3" json:"descriptor_set,omitempty"`
+	//
+	//when set, the proto files in the vendor dir before vendoring are compared with the vendored files once
+	//vendoring is done, and any incompatible changes are reported.
+	BreakingChanges *BreakingChanges `protobuf:"bytes,8,opt,name=breaking_changes,json=breakingChanges,proto3" json:"breaking_changes,omitempty"`
+	//
+	//By default the top level LICENSE, NOTICE and COPYING files of every module are vendored verbatim along with
+	//its files, without being transformed, patched or stamped, and an aggregated THIRD_PARTY_NOTICES file listing
+	//the license of every module is written to the vendor dir. Set to skip both.
+	SkipLicenses bool `protobuf:"varint,9,opt,name=skip_licenses,json=skipLicenses,proto3" json:"skip_licenses,omitempty"`
+	//
+	//prepend a comment header to every vendored .proto, .yaml and .yml file recording the module it was vendored
+	//from, its version, the path of the file within the module, and the version of anyvendor. The header of json
+	//files, which cannot contain comments, is written to a <file>.provenance.json sidecar file instead. Files
+	//vendored from the local module, and other file types, are not stamped.
+	ProvenanceHeaders bool `protobuf:"varint,10,opt,name=provenance_headers,json=provenanceHeaders,proto3" json:"provenance_headers,omitempty"`
+	//
+	//by default a .anyvendor.json file is written to the vendored root of every module (vendor_any/<module path>),
+	//recording the module, its version and commit, the patterns used, and the path and sha256 of every vendored
+	//file. Set to skip writing it.
+	SkipMetadata bool `protobuf:"varint,11,opt,name=skip_metadata,json=skipMetadata,proto3" json:"skip_metadata,omitempty"`
+	//
+	//config files, relative to this one, which are loaded by config.LoadConfig and merged beneath this config, in
+	//order, before it is validated. Included files may include others. This config overlays the included ones:
+	//imports of a go mod package which is already imported replace its patterns, patches, transforms and shade
+	//when they are set, other imports are appended, as are the local patterns and any other lists, and other
+	//fields replace the included values when they are set.
+	Includes []string `protobuf:"bytes,12,rep,name=includes,proto3" json:"includes,omitempty"`
+	//
+	//when resolving imports, skip the well known imports (google/protobuf/*), which are assumed to be provided by
+	//protoc rather than vendored. The skipped imports are logged.
+	SkipWellKnownImports bool     `protobuf:"varint,13,opt,name=skip_well_known_imports,json=skipWellKnownImports,proto3" json:"skip_well_known_imports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -83,13 +156,484 @@ func (m *Config) GetSettings() *FactorySettings {
 	return nil
 }
 
+func (m *Config) GetResolveImports() bool {
+	if m != nil {
+		return m.ResolveImports
+	}
+	return false
+}
+
+func (m *Config) GetValidateImports() *ImportValidation {
+	if m != nil {
+		return m.ValidateImports
+	}
+	return nil
+}
+
+func (m *Config) GetPatch() *Patch {
+	if m != nil {
+		return m.Patch
+	}
+	return nil
+}
+
+func (m *Config) GetDescriptorSet() *DescriptorSet {
+	if m != nil {
+		return m.DescriptorSet
+	}
+	return nil
+}
+
+func (m *Config) GetBreakingChanges() *BreakingChanges {
+	if m != nil {
+		return m.BreakingChanges
+	}
+	return nil
+}
+
+func (m *Config) GetSkipLicenses() bool {
+	if m != nil {
+		return m.SkipLicenses
+	}
+	return false
+}
+
+func (m *Config) GetProvenanceHeaders() bool {
+	if m != nil {
+		return m.ProvenanceHeaders
+	}
+	return false
+}
+
+func (m *Config) GetSkipMetadata() bool {
+	if m != nil {
+		return m.SkipMetadata
+	}
+	return false
+}
+
+func (m *Config) GetIncludes() []string {
+	if m != nil {
+		return m.Includes
+	}
+	return nil
+}
+
+func (m *Config) GetSkipWellKnownImports() bool {
+	if m != nil {
+		return m.SkipWellKnownImports
+	}
+	return false
+}
+
+// Settings for detecting breaking changes to the vendored proto files.
+//
+// The previous files are those in the vendor dir before vendoring, e.g. the files committed at git HEAD.
+// Removed files, renamed packages, removed messages and fields, and fields whose number or type changed are
+// reported. Files are matched by their path relative to the deepest include root which contains them.
+type BreakingChanges struct {
+	// fail vendoring when breaking changes are found, or when either set of files fails to compile, instead of logging them
+	Strict               bool     `protobuf:"varint,1,opt,name=strict,proto3" json:"strict,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BreakingChanges) Reset()         { *m = BreakingChanges{} }
+func (m *BreakingChanges) String() string { return proto.CompactTextString(m) }
+func (*BreakingChanges) ProtoMessage()    {}
+func (*BreakingChanges) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{1}
+}
+
+func (m *BreakingChanges) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakingChanges.Unmarshal(m, b)
+}
+func (m *BreakingChanges) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakingChanges.Marshal(b, m, deterministic)
+}
+func (m *BreakingChanges) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakingChanges.Merge(m, src)
+}
+func (m *BreakingChanges) XXX_Size() int {
+	return xxx_messageInfo_BreakingChanges.Size(m)
+}
+func (m *BreakingChanges) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakingChanges.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakingChanges proto.InternalMessageInfo
+
+func (m *BreakingChanges) GetStrict() bool {
+	if m != nil {
+		return m.Strict
+	}
+	return false
+}
+
+// Output settings for the FileDescriptorSet compiled from the vendored proto files.
+//
+// Files are compiled with the same include roots used to validate imports, including its extra roots. They
+// are named by the path the other vendored files import them by, or else by their path relative to the
+// deepest include root which contains them, and Ensure fails if a file is imported by two different paths.
+// Ensure fails, listing every error, if any file fails to compile.
+type DescriptorSet struct {
+	// path of the serialized FileDescriptorSet, relative paths are resolved against the working directory
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// include source code info, the locations and comments of every element
+	IncludeSourceInfo bool `protobuf:"varint,2,opt,name=include_source_info,json=includeSourceInfo,proto3" json:"include_source_info,omitempty"`
+	// include the descriptors of imported files which were not vendored, e.g. the well known types
+	IncludeImports       bool     `protobuf:"varint,3,opt,name=include_imports,json=includeImports,proto3" json:"include_imports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescriptorSet) Reset()         { *m = DescriptorSet{} }
+func (m *DescriptorSet) String() string { return proto.CompactTextString(m) }
+func (*DescriptorSet) ProtoMessage()    {}
+func (*DescriptorSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{2}
+}
+
+func (m *DescriptorSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescriptorSet.Unmarshal(m, b)
+}
+func (m *DescriptorSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescriptorSet.Marshal(b, m, deterministic)
+}
+func (m *DescriptorSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescriptorSet.Merge(m, src)
+}
+func (m *DescriptorSet) XXX_Size() int {
+	return xxx_messageInfo_DescriptorSet.Size(m)
+}
+func (m *DescriptorSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescriptorSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescriptorSet proto.InternalMessageInfo
+
+func (m *DescriptorSet) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *DescriptorSet) GetIncludeSourceInfo() bool {
+	if m != nil {
+		return m.IncludeSourceInfo
+	}
+	return false
+}
+
+func (m *DescriptorSet) GetIncludeImports() bool {
+	if m != nil {
+		return m.IncludeImports
+	}
+	return false
+}
+
+// Settings for validating the imports of vendored proto files.
+//
+// The include roots are the vendor directory itself, the vendored root of every module
+// (vendor_any/<module path>), and any extra roots supplied here.
+type ImportValidation struct {
+	// extra include roots, relative paths are resolved against the working directory
+	ExtraRoots           []string `protobuf:"bytes,1,rep,name=extra_roots,json=extraRoots,proto3" json:"extra_roots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportValidation) Reset()         { *m = ImportValidation{} }
+func (m *ImportValidation) String() string { return proto.CompactTextString(m) }
+func (*ImportValidation) ProtoMessage()    {}
+func (*ImportValidation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{3}
+}
+
+func (m *ImportValidation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportValidation.Unmarshal(m, b)
+}
+func (m *ImportValidation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportValidation.Marshal(b, m, deterministic)
+}
+func (m *ImportValidation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportValidation.Merge(m, src)
+}
+func (m *ImportValidation) XXX_Size() int {
+	return xxx_messageInfo_ImportValidation.Size(m)
+}
+func (m *ImportValidation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportValidation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportValidation proto.InternalMessageInfo
+
+func (m *ImportValidation) GetExtraRoots() []string {
+	if m != nil {
+		return m.ExtraRoots
+	}
+	return nil
+}
+
+// Patches which are applied to the vendored files once they have been copied.
+type Patch struct {
+	//
+	//Ordered list of rules used to set the go_package option of vendored proto files.
+	//The first rule whose pattern matches a file is applied, files matching no rule are left untouched.
+	GoPackageRules []*GoPackageRule `protobuf:"bytes,1,rep,name=go_package_rules,json=goPackageRules,proto3" json:"go_package_rules,omitempty"`
+	//
+	//When set, the go_package rules are written to a mapping file instead of being applied to the vendored
+	//files, so protoc-gen-go can be driven without editing them.
+	GoPackageMapping *GoPackageMapping `protobuf:"bytes,2,opt,name=go_package_mapping,json=goPackageMapping,proto3" json:"go_package_mapping,omitempty"`
+	//
+	//rewrites applied to the import statements of every vendored proto file, including the files vendored from
+	//the local module, so imports of relocated files still resolve. Unless rewrite_local is set, only the copies
+	//in vendor_any are rewritten, so the imports of the source protos of the local module must already use the
+	//rewritten paths to compile against the vendored tree.
+	ImportRewrites []*ImportRewrite `protobuf:"bytes,3,rep,name=import_rewrites,json=importRewrites,proto3" json:"import_rewrites,omitempty"`
+	//
+	//When set, the import rewrites are also applied to the source protos of the local module, which are
+	//edited in place.
+	RewriteLocal         bool     `protobuf:"varint,4,opt,name=rewrite_local,json=rewriteLocal,proto3" json:"rewrite_local,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Patch) Reset()         { *m = Patch{} }
+func (m *Patch) String() string { return proto.CompactTextString(m) }
+func (*Patch) ProtoMessage()    {}
+func (*Patch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{4}
+}
+
+func (m *Patch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Patch.Unmarshal(m, b)
+}
+func (m *Patch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Patch.Marshal(b, m, deterministic)
+}
+func (m *Patch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Patch.Merge(m, src)
+}
+func (m *Patch) XXX_Size() int {
+	return xxx_messageInfo_Patch.Size(m)
+}
+func (m *Patch) XXX_DiscardUnknown() {
+	xxx_messageInfo_Patch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Patch proto.InternalMessageInfo
+
+func (m *Patch) GetGoPackageRules() []*GoPackageRule {
+	if m != nil {
+		return m.GoPackageRules
+	}
+	return nil
+}
+
+func (m *Patch) GetGoPackageMapping() *GoPackageMapping {
+	if m != nil {
+		return m.GoPackageMapping
+	}
+	return nil
+}
+
+func (m *Patch) GetImportRewrites() []*ImportRewrite {
+	if m != nil {
+		return m.ImportRewrites
+	}
+	return nil
+}
+
+func (m *Patch) GetRewriteLocal() bool {
+	if m != nil {
+		return m.RewriteLocal
+	}
+	return false
+}
+
+// Maps an imported path to a new one. When from ends with a "/" it is a directory, and every import beneath it
+// is rewritten, e.g. from: validate/ to: github.com/envoyproxy/protoc-gen-validate/validate/.
+// Exact mappings take precedence over directories, and longer directories over shorter ones.
+type ImportRewrite struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportRewrite) Reset()         { *m = ImportRewrite{} }
+func (m *ImportRewrite) String() string { return proto.CompactTextString(m) }
+func (*ImportRewrite) ProtoMessage()    {}
+func (*ImportRewrite) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{5}
+}
+
+func (m *ImportRewrite) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportRewrite.Unmarshal(m, b)
+}
+func (m *ImportRewrite) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportRewrite.Marshal(b, m, deterministic)
+}
+func (m *ImportRewrite) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRewrite.Merge(m, src)
+}
+func (m *ImportRewrite) XXX_Size() int {
+	return xxx_messageInfo_ImportRewrite.Size(m)
+}
+func (m *ImportRewrite) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRewrite.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRewrite proto.InternalMessageInfo
+
+func (m *ImportRewrite) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *ImportRewrite) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+// Output files for the go_package rules.
+//
+// The mapping file contains one M<file>=<go package> line per vendored proto file, each of which can be
+// passed to protoc-gen-go, e.g. --go_opt=M<file>=<go package>.
+type GoPackageMapping struct {
+	// path of the mapping file, relative paths are resolved against the working directory
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// optional path of a buf.gen.yaml snippet with a managed mode go_package override per file
+	BufPath string `protobuf:"bytes,2,opt,name=buf_path,json=bufPath,proto3" json:"buf_path,omitempty"`
+	// key the mapping by the path of the file relative to its module, rather than relative to the vendor dir.
+	// Vendoring fails if files of different modules have the same relative path but a different go_package.
+	ModuleRelative       bool     `protobuf:"varint,3,opt,name=module_relative,json=moduleRelative,proto3" json:"module_relative,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GoPackageMapping) Reset()         { *m = GoPackageMapping{} }
+func (m *GoPackageMapping) String() string { return proto.CompactTextString(m) }
+func (*GoPackageMapping) ProtoMessage()    {}
+func (*GoPackageMapping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{6}
+}
+
+func (m *GoPackageMapping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GoPackageMapping.Unmarshal(m, b)
+}
+func (m *GoPackageMapping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GoPackageMapping.Marshal(b, m, deterministic)
+}
+func (m *GoPackageMapping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GoPackageMapping.Merge(m, src)
+}
+func (m *GoPackageMapping) XXX_Size() int {
+	return xxx_messageInfo_GoPackageMapping.Size(m)
+}
+func (m *GoPackageMapping) XXX_DiscardUnknown() {
+	xxx_messageInfo_GoPackageMapping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GoPackageMapping proto.InternalMessageInfo
+
+func (m *GoPackageMapping) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *GoPackageMapping) GetBufPath() string {
+	if m != nil {
+		return m.BufPath
+	}
+	return ""
+}
+
+func (m *GoPackageMapping) GetModuleRelative() bool {
+	if m != nil {
+		return m.ModuleRelative
+	}
+	return false
+}
+
+// A rule which sets the go_package option of the vendored proto files it matches.
+//
+// The go_package template may contain the following placeholders:
+// {dir}: directory of the file relative to the vendor dir, e.g. github.com/solo-io/solo-kit/api/v1
+// {module}: the module the file was vendored from, e.g. github.com/solo-io/solo-kit
+// {file}: name of the file without its extension, e.g. metadata
+type GoPackageRule struct {
+	// glob matched against the path of the file relative to the vendor dir, e.g. github.com/solo-io/solo-kit/api/**/*.proto
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// template for the go_package option, e.g. github.com/solo-io/solo-kit/pkg/api/{file}
+	GoPackage            string   `protobuf:"bytes,2,opt,name=go_package,json=goPackage,proto3" json:"go_package,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GoPackageRule) Reset()         { *m = GoPackageRule{} }
+func (m *GoPackageRule) String() string { return proto.CompactTextString(m) }
+func (*GoPackageRule) ProtoMessage()    {}
+func (*GoPackageRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{7}
+}
+
+func (m *GoPackageRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GoPackageRule.Unmarshal(m, b)
+}
+func (m *GoPackageRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GoPackageRule.Marshal(b, m, deterministic)
+}
+func (m *GoPackageRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GoPackageRule.Merge(m, src)
+}
+func (m *GoPackageRule) XXX_Size() int {
+	return xxx_messageInfo_GoPackageRule.Size(m)
+}
+func (m *GoPackageRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_GoPackageRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GoPackageRule proto.InternalMessageInfo
+
+func (m *GoPackageRule) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *GoPackageRule) GetGoPackage() string {
+	if m != nil {
+		return m.GoPackage
+	}
+	return ""
+}
+
 // a message for settings which is passed to the factories at startup
 type FactorySettings struct {
 	// Example: [**/node_modules/**]
 	// Any paths which start the string `node_modules` will be skipped over by the copier.
 	SkipPatterns []string `protobuf:"bytes,1,rep,name=skip_patterns,json=skipPatterns,proto3" json:"skip_patterns,omitempty"`
 	// Current working directory
-	Cwd                  string   `protobuf:"bytes,2,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	Cwd string `protobuf:"bytes,2,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	// when set, every vendored source must comply with the policy, otherwise vendoring fails listing the violations
+	Policy               *Policy  `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -99,7 +643,7 @@ func (m *FactorySettings) Reset()         { *m = FactorySettings{} }
 func (m *FactorySettings) String() string { return proto.CompactTextString(m) }
 func (*FactorySettings) ProtoMessage()    {}
 func (*FactorySettings) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2a8ec572c73c9b71, []int{1}
+	return fileDescriptor_2a8ec572c73c9b71, []int{8}
 }
 
 func (m *FactorySettings) XXX_Unmarshal(b []byte) error {
@@ -134,6 +678,75 @@ func (m *FactorySettings) GetCwd() string {
 	return ""
 }
 
+func (m *FactorySettings) GetPolicy() *Policy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+// A policy restricting where vendored files may come from. Every list which is empty allows everything.
+type Policy struct {
+	//
+	//module paths, or git repository URLs, which may be vendored from. Patterns without wildcards match as a
+	//prefix, e.g. github.com/solo-io allows every module under github.com/solo-io. URLs are also matched
+	//without their scheme. The local module is always allowed.
+	AllowedSources []string `protobuf:"bytes,1,rep,name=allowed_sources,json=allowedSources,proto3" json:"allowed_sources,omitempty"`
+	// SPDX identifiers of the licenses sources may have, e.g. Apache-2.0. Sources whose license is not detected are not allowed.
+	AllowedLicenses []string `protobuf:"bytes,2,rep,name=allowed_licenses,json=allowedLicenses,proto3" json:"allowed_licenses,omitempty"`
+	// globs matched against the path of every vendored file, relative to the root of its source
+	DeniedPaths          []string `protobuf:"bytes,3,rep,name=denied_paths,json=deniedPaths,proto3" json:"denied_paths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Policy) Reset()         { *m = Policy{} }
+func (m *Policy) String() string { return proto.CompactTextString(m) }
+func (*Policy) ProtoMessage()    {}
+func (*Policy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{9}
+}
+
+func (m *Policy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy.Unmarshal(m, b)
+}
+func (m *Policy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy.Marshal(b, m, deterministic)
+}
+func (m *Policy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy.Merge(m, src)
+}
+func (m *Policy) XXX_Size() int {
+	return xxx_messageInfo_Policy.Size(m)
+}
+func (m *Policy) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Policy proto.InternalMessageInfo
+
+func (m *Policy) GetAllowedSources() []string {
+	if m != nil {
+		return m.AllowedSources
+	}
+	return nil
+}
+
+func (m *Policy) GetAllowedLicenses() []string {
+	if m != nil {
+		return m.AllowedLicenses
+	}
+	return nil
+}
+
+func (m *Policy) GetDeniedPaths() []string {
+	if m != nil {
+		return m.DeniedPaths
+	}
+	return nil
+}
+
 type Import struct {
 	// Types that are valid to be assigned to ImportType:
 	//	*Import_GoMod
@@ -147,7 +760,7 @@ func (m *Import) Reset()         { *m = Import{} }
 func (m *Import) String() string { return proto.CompactTextString(m) }
 func (*Import) ProtoMessage()    {}
 func (*Import) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2a8ec572c73c9b71, []int{2}
+	return fileDescriptor_2a8ec572c73c9b71, []int{10}
 }
 
 func (m *Import) XXX_Unmarshal(b []byte) error {
@@ -211,7 +824,7 @@ func (m *Local) Reset()         { *m = Local{} }
 func (m *Local) String() string { return proto.CompactTextString(m) }
 func (*Local) ProtoMessage()    {}
 func (*Local) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2a8ec572c73c9b71, []int{3}
+	return fileDescriptor_2a8ec572c73c9b71, []int{11}
 }
 
 func (m *Local) XXX_Unmarshal(b []byte) error {
@@ -248,8 +861,21 @@ func (m *Local) GetPatterns() []string {
 // The GoModImport uses the command `go list -f '{{.Path}}' -m  all` to find
 // all of the package names
 type GoModImport struct {
-	Patterns             []string `protobuf:"bytes,1,rep,name=patterns,proto3" json:"patterns,omitempty"`
-	Package              string   `protobuf:"bytes,2,opt,name=package,proto3" json:"package,omitempty"`
+	Patterns []string `protobuf:"bytes,1,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	Package  string   `protobuf:"bytes,2,opt,name=package,proto3" json:"package,omitempty"`
+	//
+	//unified diff files, relative to the working directory, which are applied in order to the files vendored
+	//from this module once they have been copied. File names in the patches are relative to the vendored root
+	//of the module (vendor_any/<module path>), and may have git style a/ and b/ prefixes.
+	Patches []string `protobuf:"bytes,3,rep,name=patches,proto3" json:"patches,omitempty"`
+	//
+	//transforms which are run, in order, on the path and contents of every file vendored from this module as it
+	//is copied. Patches are applied to the transformed files.
+	Transforms []*Transform `protobuf:"bytes,4,rep,name=transforms,proto3" json:"transforms,omitempty"`
+	//
+	//when set, the proto files vendored from this module are shaded, so they can be vendored alongside another
+	//version of the same files. Shading happens once the files have been transformed and patched.
+	Shade                *Shade   `protobuf:"bytes,5,opt,name=shade,proto3" json:"shade,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -259,7 +885,7 @@ func (m *GoModImport) Reset()         { *m = GoModImport{} }
 func (m *GoModImport) String() string { return proto.CompactTextString(m) }
 func (*GoModImport) ProtoMessage()    {}
 func (*GoModImport) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2a8ec572c73c9b71, []int{4}
+	return fileDescriptor_2a8ec572c73c9b71, []int{12}
 }
 
 func (m *GoModImport) XXX_Unmarshal(b []byte) error {
@@ -294,38 +920,440 @@ func (m *GoModImport) GetPackage() string {
 	return ""
 }
 
+func (m *GoModImport) GetPatches() []string {
+	if m != nil {
+		return m.Patches
+	}
+	return nil
+}
+
+func (m *GoModImport) GetTransforms() []*Transform {
+	if m != nil {
+		return m.Transforms
+	}
+	return nil
+}
+
+func (m *GoModImport) GetShade() *Shade {
+	if m != nil {
+		return m.Shade
+	}
+	return nil
+}
+
+// Shading relocates the vendored proto files of a module under an alternate import path and proto package.
+//
+// Files are moved from vendor_any/<module path>/<path> to vendor_any/<path prefix>/<path>, their package
+// declaration is prefixed with the package prefix, and their imports of each other, and references to each
+// other's types, are rewritten to match. The files must import each other relative to the module root.
+type Shade struct {
+	// prefix added to the proto package of every file, e.g. v1 shades envoy.api.v2 as v1.envoy.api.v2
+	PackagePrefix string `protobuf:"bytes,1,opt,name=package_prefix,json=packagePrefix,proto3" json:"package_prefix,omitempty"`
+	// prefix added to the import path of every file, e.g. v1 shades envoy/api/v2/cds.proto as v1/envoy/api/v2/cds.proto
+	PathPrefix           string   `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Shade) Reset()         { *m = Shade{} }
+func (m *Shade) String() string { return proto.CompactTextString(m) }
+func (*Shade) ProtoMessage()    {}
+func (*Shade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{13}
+}
+
+func (m *Shade) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Shade.Unmarshal(m, b)
+}
+func (m *Shade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Shade.Marshal(b, m, deterministic)
+}
+func (m *Shade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Shade.Merge(m, src)
+}
+func (m *Shade) XXX_Size() int {
+	return xxx_messageInfo_Shade.Size(m)
+}
+func (m *Shade) XXX_DiscardUnknown() {
+	xxx_messageInfo_Shade.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Shade proto.InternalMessageInfo
+
+func (m *Shade) GetPackagePrefix() string {
+	if m != nil {
+		return m.PackagePrefix
+	}
+	return ""
+}
+
+func (m *Shade) GetPathPrefix() string {
+	if m != nil {
+		return m.PathPrefix
+	}
+	return ""
+}
+
+// A transform which is run on vendored files as they are copied.
+//
+// The path passed to a transform is relative to the vendored root of the module (vendor_any/<module path>).
+type Transform struct {
+	// only transform files whose path matches one of these globs, every file is transformed when empty
+	Patterns []string `protobuf:"bytes,1,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	// Types that are valid to be assigned to TransformType:
+	//	*Transform_RegexReplace
+	//	*Transform_LineEndings
+	//	*Transform_StripProtoOptions
+	TransformType        isTransform_TransformType `protobuf_oneof:"TransformType"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *Transform) Reset()         { *m = Transform{} }
+func (m *Transform) String() string { return proto.CompactTextString(m) }
+func (*Transform) ProtoMessage()    {}
+func (*Transform) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{14}
+}
+
+func (m *Transform) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transform.Unmarshal(m, b)
+}
+func (m *Transform) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transform.Marshal(b, m, deterministic)
+}
+func (m *Transform) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transform.Merge(m, src)
+}
+func (m *Transform) XXX_Size() int {
+	return xxx_messageInfo_Transform.Size(m)
+}
+func (m *Transform) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transform.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transform proto.InternalMessageInfo
+
+func (m *Transform) GetPatterns() []string {
+	if m != nil {
+		return m.Patterns
+	}
+	return nil
+}
+
+type isTransform_TransformType interface {
+	isTransform_TransformType()
+}
+
+type Transform_RegexReplace struct {
+	RegexReplace *RegexReplace `protobuf:"bytes,2,opt,name=regex_replace,json=regexReplace,proto3,oneof"`
+}
+
+type Transform_LineEndings struct {
+	LineEndings *LineEndings `protobuf:"bytes,3,opt,name=line_endings,json=lineEndings,proto3,oneof"`
+}
+
+type Transform_StripProtoOptions struct {
+	StripProtoOptions *StripProtoOptions `protobuf:"bytes,4,opt,name=strip_proto_options,json=stripProtoOptions,proto3,oneof"`
+}
+
+func (*Transform_RegexReplace) isTransform_TransformType() {}
+
+func (*Transform_LineEndings) isTransform_TransformType() {}
+
+func (*Transform_StripProtoOptions) isTransform_TransformType() {}
+
+func (m *Transform) GetTransformType() isTransform_TransformType {
+	if m != nil {
+		return m.TransformType
+	}
+	return nil
+}
+
+func (m *Transform) GetRegexReplace() *RegexReplace {
+	if x, ok := m.GetTransformType().(*Transform_RegexReplace); ok {
+		return x.RegexReplace
+	}
+	return nil
+}
+
+func (m *Transform) GetLineEndings() *LineEndings {
+	if x, ok := m.GetTransformType().(*Transform_LineEndings); ok {
+		return x.LineEndings
+	}
+	return nil
+}
+
+func (m *Transform) GetStripProtoOptions() *StripProtoOptions {
+	if x, ok := m.GetTransformType().(*Transform_StripProtoOptions); ok {
+		return x.StripProtoOptions
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Transform) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Transform_RegexReplace)(nil),
+		(*Transform_LineEndings)(nil),
+		(*Transform_StripProtoOptions)(nil),
+	}
+}
+
+// replace every match of a regular expression in the contents, or path, of the file
+type RegexReplace struct {
+	// RE2 regular expression, as accepted by the go regexp package
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// replacement text, which may reference submatches, e.g. ${1}
+	Replacement string `protobuf:"bytes,2,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	// replace matches in the path of the file instead of its contents, this renames the vendored file
+	Path                 bool     `protobuf:"varint,3,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegexReplace) Reset()         { *m = RegexReplace{} }
+func (m *RegexReplace) String() string { return proto.CompactTextString(m) }
+func (*RegexReplace) ProtoMessage()    {}
+func (*RegexReplace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{15}
+}
+
+func (m *RegexReplace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegexReplace.Unmarshal(m, b)
+}
+func (m *RegexReplace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegexReplace.Marshal(b, m, deterministic)
+}
+func (m *RegexReplace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegexReplace.Merge(m, src)
+}
+func (m *RegexReplace) XXX_Size() int {
+	return xxx_messageInfo_RegexReplace.Size(m)
+}
+func (m *RegexReplace) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegexReplace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegexReplace proto.InternalMessageInfo
+
+func (m *RegexReplace) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *RegexReplace) GetReplacement() string {
+	if m != nil {
+		return m.Replacement
+	}
+	return ""
+}
+
+func (m *RegexReplace) GetPath() bool {
+	if m != nil {
+		return m.Path
+	}
+	return false
+}
+
+// normalize the line endings of text files, files containing NUL bytes are left untouched
+type LineEndings struct {
+	Style                LineEndings_Style `protobuf:"varint,1,opt,name=style,proto3,enum=anyvendor.LineEndings_Style" json:"style,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LineEndings) Reset()         { *m = LineEndings{} }
+func (m *LineEndings) String() string { return proto.CompactTextString(m) }
+func (*LineEndings) ProtoMessage()    {}
+func (*LineEndings) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{16}
+}
+
+func (m *LineEndings) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LineEndings.Unmarshal(m, b)
+}
+func (m *LineEndings) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LineEndings.Marshal(b, m, deterministic)
+}
+func (m *LineEndings) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LineEndings.Merge(m, src)
+}
+func (m *LineEndings) XXX_Size() int {
+	return xxx_messageInfo_LineEndings.Size(m)
+}
+func (m *LineEndings) XXX_DiscardUnknown() {
+	xxx_messageInfo_LineEndings.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LineEndings proto.InternalMessageInfo
+
+func (m *LineEndings) GetStyle() LineEndings_Style {
+	if m != nil {
+		return m.Style
+	}
+	return LineEndings_LF
+}
+
+// remove options from proto files: the named file options, e.g. go_package or (gogoproto.goproto_getters_all),
+// and every option, at any level, which sets an extension from one of the packages, e.g. (validate.rules) or
+// (gogoproto.nullable). Imports which are no longer referenced once the options are removed are removed too.
+// The package of an import is read from the imported file, which is looked up in the vendored modules, and
+// imports which cannot be found are kept.
+type StripProtoOptions struct {
+	// file options which are removed
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	// proto packages whose extensions are removed, e.g. validate or gogoproto
+	Packages             []string `protobuf:"bytes,2,rep,name=packages,proto3" json:"packages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StripProtoOptions) Reset()         { *m = StripProtoOptions{} }
+func (m *StripProtoOptions) String() string { return proto.CompactTextString(m) }
+func (*StripProtoOptions) ProtoMessage()    {}
+func (*StripProtoOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2a8ec572c73c9b71, []int{17}
+}
+
+func (m *StripProtoOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StripProtoOptions.Unmarshal(m, b)
+}
+func (m *StripProtoOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StripProtoOptions.Marshal(b, m, deterministic)
+}
+func (m *StripProtoOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StripProtoOptions.Merge(m, src)
+}
+func (m *StripProtoOptions) XXX_Size() int {
+	return xxx_messageInfo_StripProtoOptions.Size(m)
+}
+func (m *StripProtoOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_StripProtoOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StripProtoOptions proto.InternalMessageInfo
+
+func (m *StripProtoOptions) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *StripProtoOptions) GetPackages() []string {
+	if m != nil {
+		return m.Packages
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("anyvendor.LineEndings_Style", LineEndings_Style_name, LineEndings_Style_value)
 	proto.RegisterType((*Config)(nil), "anyvendor.Config")
+	proto.RegisterType((*BreakingChanges)(nil), "anyvendor.BreakingChanges")
+	proto.RegisterType((*DescriptorSet)(nil), "anyvendor.DescriptorSet")
+	proto.RegisterType((*ImportValidation)(nil), "anyvendor.ImportValidation")
+	proto.RegisterType((*Patch)(nil), "anyvendor.Patch")
+	proto.RegisterType((*ImportRewrite)(nil), "anyvendor.ImportRewrite")
+	proto.RegisterType((*GoPackageMapping)(nil), "anyvendor.GoPackageMapping")
+	proto.RegisterType((*GoPackageRule)(nil), "anyvendor.GoPackageRule")
 	proto.RegisterType((*FactorySettings)(nil), "anyvendor.FactorySettings")
+	proto.RegisterType((*Policy)(nil), "anyvendor.Policy")
 	proto.RegisterType((*Import)(nil), "anyvendor.Import")
 	proto.RegisterType((*Local)(nil), "anyvendor.Local")
 	proto.RegisterType((*GoModImport)(nil), "anyvendor.GoModImport")
+	proto.RegisterType((*Shade)(nil), "anyvendor.Shade")
+	proto.RegisterType((*Transform)(nil), "anyvendor.Transform")
+	proto.RegisterType((*RegexReplace)(nil), "anyvendor.RegexReplace")
+	proto.RegisterType((*LineEndings)(nil), "anyvendor.LineEndings")
+	proto.RegisterType((*StripProtoOptions)(nil), "anyvendor.StripProtoOptions")
 }
 
 func init() { proto.RegisterFile("anyvendor.proto", fileDescriptor_2a8ec572c73c9b71) }
 
 var fileDescriptor_2a8ec572c73c9b71 = []byte{
-	// 346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0xc1, 0x4a, 0xeb, 0x40,
-	0x14, 0x86, 0xef, 0x24, 0x37, 0x69, 0x73, 0x72, 0x2f, 0xad, 0x23, 0x68, 0xe8, 0x2a, 0xa4, 0x50,
-	0x02, 0x62, 0x03, 0x15, 0x7c, 0x80, 0x08, 0x5a, 0x41, 0x45, 0x46, 0x57, 0xdd, 0x94, 0x69, 0x12,
-	0x63, 0x68, 0x9a, 0x13, 0x92, 0xb1, 0xd2, 0xd7, 0x10, 0x7c, 0x17, 0x71, 0xd5, 0xd7, 0xe9, 0x5b,
-	0x48, 0x92, 0x36, 0x8d, 0xae, 0xdc, 0x9d, 0xf9, 0xbf, 0x6f, 0x0e, 0xe7, 0xcc, 0x40, 0x87, 0x27,
-	0xab, 0x65, 0x90, 0xf8, 0x98, 0x0d, 0xd3, 0x0c, 0x05, 0x52, 0xad, 0x0e, 0x7a, 0xc7, 0x4b, 0x1e,
-	0x47, 0x3e, 0x17, 0x81, 0xb3, 0x2b, 0x2a, 0xc7, 0x7a, 0x27, 0xa0, 0x5e, 0x60, 0xf2, 0x14, 0x85,
-	0x74, 0x00, 0x4a, 0x8c, 0x1e, 0x8f, 0x0d, 0x62, 0x12, 0x5b, 0x1f, 0x75, 0x87, 0xfb, 0x7e, 0x37,
-	0x45, 0xce, 0x2a, 0x4c, 0x4f, 0xa0, 0x15, 0x2d, 0x52, 0xcc, 0x44, 0x6e, 0x48, 0xa6, 0x6c, 0xeb,
-	0xa3, 0x83, 0x86, 0x79, 0x5d, 0x12, 0xb6, 0x33, 0xe8, 0x39, 0xb4, 0xf3, 0x40, 0x88, 0x28, 0x09,
-	0x73, 0x43, 0x2e, 0xfb, 0xf6, 0x1a, 0xf6, 0x25, 0xf7, 0x04, 0x66, 0xab, 0x87, 0xad, 0xc1, 0x6a,
-	0xd7, 0x1a, 0x43, 0xe7, 0x07, 0xa4, 0x7d, 0xf8, 0x9f, 0xcf, 0xa3, 0x74, 0x9a, 0x72, 0x21, 0x82,
-	0x2c, 0xc9, 0x0d, 0x62, 0xca, 0xb6, 0xc6, 0xfe, 0x15, 0xe1, 0xfd, 0x36, 0xa3, 0x5d, 0x90, 0xbd,
-	0x57, 0xdf, 0x90, 0x4c, 0x62, 0x6b, 0xac, 0x28, 0xad, 0x3b, 0x50, 0xab, 0xa1, 0xa8, 0x03, 0x6a,
-	0x88, 0xd3, 0x05, 0x56, 0x58, 0x1f, 0x1d, 0x35, 0x26, 0xb9, 0xc2, 0x5b, 0xf4, 0x2b, 0x6f, 0xfc,
-	0x87, 0x29, 0x61, 0x71, 0x74, 0x0f, 0x01, 0xaa, 0xe8, 0x71, 0x95, 0x06, 0x54, 0xf9, 0xd8, 0xac,
-	0x65, 0x62, 0x39, 0xa0, 0x94, 0xcf, 0x41, 0x07, 0xd0, 0xfe, 0x3e, 0x8a, 0x0b, 0x9f, 0x9b, 0xb5,
-	0xac, 0xbc, 0x11, 0xa9, 0x4d, 0x58, 0xcd, 0xac, 0x09, 0xe8, 0x8d, 0xee, 0xbf, 0xbd, 0x46, 0xfb,
-	0xd0, 0x4a, 0xb9, 0x37, 0xe7, 0x61, 0x50, 0x6d, 0xe3, 0x6a, 0x85, 0xf6, 0x37, 0x93, 0xba, 0x84,
-	0xed, 0x88, 0x6b, 0x4f, 0x06, 0x61, 0x24, 0x9e, 0x5f, 0x66, 0x43, 0x0f, 0x17, 0x4e, 0x8e, 0x31,
-	0x9e, 0x46, 0xe8, 0xd4, 0x6b, 0xed, 0xab, 0x99, 0x5a, 0xfe, 0xf7, 0xd9, 0x57, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xd8, 0x54, 0x01, 0x14, 0x26, 0x02, 0x00, 0x00,
+	// 1195 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0x5b, 0x6f, 0x1b, 0x45,
+	0x14, 0x8e, 0x9d, 0xac, 0x63, 0x1f, 0xc7, 0xf6, 0x66, 0x1a, 0x35, 0xdb, 0x16, 0x89, 0x74, 0x41,
+	0x69, 0x2a, 0xd4, 0x44, 0x4a, 0x81, 0x17, 0x24, 0x10, 0x2e, 0x09, 0x09, 0xa4, 0x60, 0x4d, 0x2a,
+	0x90, 0x2a, 0xa4, 0xd5, 0x64, 0x77, 0xbc, 0x5e, 0x65, 0x3d, 0xb3, 0x9a, 0x19, 0xe7, 0xf2, 0x84,
+	0x84, 0xc4, 0x3b, 0xe2, 0x87, 0xf1, 0x7b, 0x50, 0x1f, 0x10, 0x9a, 0xcb, 0xae, 0xd7, 0x36, 0x42,
+	0x7d, 0xdb, 0xf3, 0x7d, 0xdf, 0x9c, 0x99, 0x73, 0x99, 0x33, 0x0b, 0x03, 0xc2, 0xee, 0x6f, 0x28,
+	0x4b, 0xb8, 0x38, 0x2c, 0x04, 0x57, 0x1c, 0x75, 0x2a, 0xe0, 0xf1, 0xee, 0x0d, 0xc9, 0xb3, 0x84,
+	0x28, 0x7a, 0x54, 0x7e, 0x58, 0x4d, 0xf8, 0x9b, 0x07, 0xad, 0x57, 0x9c, 0x8d, 0xb3, 0x14, 0xed,
+	0x83, 0x97, 0xf3, 0x98, 0xe4, 0x41, 0x63, 0xaf, 0x71, 0xd0, 0x3d, 0xf6, 0x0f, 0xe7, 0xfe, 0x2e,
+	0x34, 0x8e, 0x2d, 0x8d, 0x3e, 0x81, 0xcd, 0x6c, 0x5a, 0x70, 0xa1, 0x64, 0xd0, 0xdc, 0x5b, 0x3f,
+	0xe8, 0x1e, 0x6f, 0xd7, 0x94, 0xe7, 0x86, 0xc1, 0xa5, 0x02, 0x7d, 0x0e, 0x6d, 0x49, 0x95, 0xca,
+	0x58, 0x2a, 0x83, 0x75, 0xe3, 0xf7, 0x71, 0x4d, 0x7d, 0x4a, 0x62, 0xc5, 0xc5, 0xfd, 0xa5, 0x53,
+	0xe0, 0x4a, 0x8b, 0x9e, 0xc1, 0x40, 0x50, 0xc9, 0xf3, 0x1b, 0x1a, 0x95, 0x9b, 0x6d, 0xec, 0x35,
+	0x0e, 0xda, 0xb8, 0xef, 0xe0, 0x73, 0xb7, 0xc1, 0x29, 0xf8, 0x65, 0x48, 0x95, 0xd2, 0x33, 0x1b,
+	0x3d, 0x59, 0x39, 0xd6, 0x4f, 0x56, 0x98, 0x71, 0x86, 0x07, 0xe5, 0xa2, 0xd2, 0xcf, 0x3e, 0x78,
+	0x05, 0x51, 0xf1, 0x24, 0x68, 0xad, 0x44, 0x3f, 0xd2, 0x38, 0xb6, 0x34, 0xfa, 0x0a, 0xfa, 0x09,
+	0x95, 0xb1, 0xc8, 0x0a, 0xc5, 0x45, 0x24, 0xa9, 0x0a, 0x36, 0xcd, 0x82, 0xa0, 0xb6, 0xe0, 0x9b,
+	0x4a, 0x70, 0x49, 0x15, 0xee, 0x25, 0x75, 0x13, 0x9d, 0x80, 0x7f, 0x25, 0x28, 0xb9, 0xce, 0x58,
+	0x1a, 0xc5, 0x13, 0xc2, 0x52, 0x2a, 0x83, 0xf6, 0x4a, 0x66, 0x86, 0x4e, 0xf2, 0xca, 0x2a, 0xf0,
+	0xe0, 0x6a, 0x11, 0x40, 0x1f, 0x41, 0x4f, 0x5e, 0x67, 0x45, 0x94, 0x67, 0x31, 0x65, 0x92, 0xca,
+	0xa0, 0x63, 0xd2, 0xb3, 0xa5, 0xc1, 0x0b, 0x87, 0xa1, 0x17, 0x80, 0x0a, 0xc1, 0x6f, 0x28, 0x23,
+	0x2c, 0xa6, 0xd1, 0x84, 0x92, 0x84, 0x0a, 0x19, 0x80, 0x51, 0x6e, 0xcf, 0x99, 0x33, 0x4b, 0x54,
+	0x3e, 0xa7, 0x54, 0x91, 0x84, 0x28, 0x12, 0x74, 0xe7, 0x3e, 0x5f, 0x3b, 0x0c, 0x3d, 0x86, 0x76,
+	0xc6, 0xe2, 0x7c, 0x96, 0x50, 0x19, 0x6c, 0xed, 0xad, 0x1f, 0x74, 0x70, 0x65, 0xa3, 0xcf, 0x60,
+	0xd7, 0x38, 0xb8, 0xa5, 0x79, 0x1e, 0x5d, 0x33, 0x7e, 0xcb, 0xaa, 0x9a, 0xf4, 0x8c, 0xab, 0x1d,
+	0x4d, 0xff, 0x4c, 0xf3, 0xfc, 0x7b, 0x4d, 0xba, 0xdc, 0x87, 0xcf, 0x61, 0xb0, 0x14, 0x2f, 0x7a,
+	0x08, 0x2d, 0xa9, 0x44, 0x16, 0x2b, 0xd3, 0x8d, 0x6d, 0xec, 0xac, 0xf0, 0xf7, 0x06, 0xf4, 0x16,
+	0xd2, 0x8b, 0x9e, 0xc0, 0x46, 0x41, 0xd4, 0xc4, 0xe8, 0x3a, 0xc3, 0xcd, 0x77, 0xc3, 0x0d, 0xd1,
+	0xf4, 0x1b, 0xd8, 0x80, 0xe8, 0x10, 0x1e, 0xb8, 0xc3, 0x45, 0x92, 0xcf, 0x44, 0x4c, 0xa3, 0x8c,
+	0x8d, 0x79, 0xd0, 0xb4, 0x19, 0x70, 0xd4, 0xa5, 0x61, 0xce, 0xd9, 0x98, 0xeb, 0xb6, 0x2b, 0xf5,
+	0xe5, 0xc1, 0xd7, 0x6d, 0xdb, 0x39, 0xb8, 0x3c, 0xf2, 0x4b, 0xf0, 0x97, 0x7b, 0x0a, 0x7d, 0x08,
+	0x5d, 0x7a, 0xa7, 0x04, 0x89, 0x04, 0xe7, 0x4a, 0x06, 0x0d, 0x93, 0x1c, 0x30, 0x10, 0xd6, 0x48,
+	0xf8, 0x4f, 0x03, 0x3c, 0xd3, 0x4c, 0x68, 0x08, 0x7e, 0xca, 0xa3, 0x82, 0xc4, 0xd7, 0x24, 0xa5,
+	0x91, 0x98, 0xe5, 0xd4, 0xea, 0x17, 0xfb, 0xe8, 0x5b, 0x3e, 0xb2, 0x0a, 0x3c, 0xcb, 0x29, 0xee,
+	0xa7, 0x75, 0x53, 0xa2, 0x73, 0x40, 0x35, 0x1f, 0x53, 0x52, 0x14, 0x19, 0x4b, 0x83, 0xe6, 0x4a,
+	0xef, 0x57, 0x5e, 0x5e, 0x5b, 0x09, 0xf6, 0xd3, 0x25, 0x04, 0x7d, 0x0d, 0x03, 0x1b, 0x6e, 0x24,
+	0xe8, 0xad, 0xc8, 0x14, 0xd5, 0x61, 0x2f, 0x9f, 0xc6, 0x5d, 0x6d, 0x2b, 0xc0, 0xfd, 0xac, 0x6e,
+	0x9a, 0xde, 0x71, 0x6b, 0x23, 0x3b, 0x45, 0xec, 0x75, 0xdd, 0x72, 0xa0, 0x99, 0x20, 0xe1, 0x09,
+	0xf4, 0x16, 0xbc, 0xe8, 0xe2, 0x8d, 0x05, 0x9f, 0xae, 0x14, 0x4f, 0x83, 0x68, 0x17, 0x9a, 0xca,
+	0xd6, 0xaa, 0x46, 0x35, 0x15, 0x0f, 0x25, 0xf8, 0xcb, 0x41, 0xfd, 0x7f, 0x1b, 0x3c, 0x82, 0xf6,
+	0xd5, 0x6c, 0x1c, 0x19, 0x81, 0xf1, 0x87, 0x37, 0xaf, 0x66, 0xe3, 0x91, 0xa6, 0x9e, 0xc1, 0x60,
+	0xca, 0x93, 0x59, 0x4e, 0x23, 0x41, 0x73, 0xa2, 0xb2, 0x1b, 0x5a, 0x56, 0xdc, 0xc2, 0xd8, 0xa1,
+	0xe1, 0x5b, 0xe8, 0x2d, 0xd4, 0x03, 0x3d, 0x85, 0xcd, 0x82, 0x28, 0x45, 0x05, 0x5b, 0xde, 0xb4,
+	0xc4, 0xd1, 0x3e, 0xc0, 0xbc, 0x44, 0xcb, 0x91, 0x74, 0xaa, 0x32, 0x84, 0x12, 0x06, 0x4b, 0xa3,
+	0xb0, 0xba, 0x8b, 0xce, 0x55, 0xd9, 0x4e, 0xe6, 0x2e, 0x8e, 0x1c, 0x86, 0x7c, 0x58, 0x8f, 0x6f,
+	0x13, 0x17, 0x92, 0xfe, 0x44, 0xcf, 0xa1, 0x55, 0xf0, 0x3c, 0x8b, 0xef, 0xdd, 0xb4, 0xad, 0xcf,
+	0xe6, 0x91, 0x21, 0xb0, 0x13, 0x84, 0xbf, 0x42, 0xcb, 0x22, 0x3a, 0x07, 0x24, 0xcf, 0xf9, 0x2d,
+	0x4d, 0xdc, 0x2d, 0x29, 0x77, 0xeb, 0x3b, 0xd8, 0xde, 0x10, 0x89, 0x9e, 0x83, 0x5f, 0x0a, 0xab,
+	0xb9, 0xd3, 0x34, 0xca, 0xd2, 0x41, 0x35, 0x7a, 0x9e, 0xc2, 0x56, 0x42, 0x59, 0x46, 0x13, 0x93,
+	0x75, 0xdb, 0x4f, 0x1d, 0xdc, 0xb5, 0x98, 0xce, 0xbc, 0x0c, 0x2f, 0xa0, 0x65, 0xbb, 0x01, 0x1d,
+	0x41, 0x2b, 0xe5, 0xd1, 0x94, 0x27, 0xae, 0x7d, 0x1f, 0x2e, 0xb4, 0xef, 0x6b, 0x9e, 0x58, 0xdd,
+	0xd9, 0x1a, 0xf6, 0x52, 0x6d, 0x0e, 0xb7, 0x01, 0x2c, 0xf4, 0xe6, 0xbe, 0xa0, 0x68, 0xfd, 0xef,
+	0x61, 0x23, 0x7c, 0x01, 0x9e, 0x69, 0x32, 0xf4, 0x31, 0xb4, 0x17, 0x93, 0x36, 0x6c, 0xbf, 0x1b,
+	0x7a, 0x7f, 0x36, 0x9a, 0xed, 0x06, 0xae, 0x98, 0xf0, 0xaf, 0x06, 0x74, 0x6b, 0xae, 0xdf, 0x6f,
+	0x95, 0xad, 0xf9, 0x7f, 0x56, 0xb3, 0xc4, 0x51, 0xa0, 0x25, 0x2a, 0x9e, 0xd0, 0x32, 0xe6, 0xd2,
+	0x44, 0x9f, 0x02, 0x28, 0x41, 0x98, 0x1c, 0x73, 0x31, 0xd5, 0xcf, 0x99, 0xbe, 0x60, 0x3b, 0xb5,
+	0x48, 0xdf, 0x94, 0x24, 0xae, 0xe9, 0xf4, 0xc3, 0x24, 0x27, 0x24, 0xa1, 0x81, 0xb7, 0xf2, 0x30,
+	0x5d, 0x6a, 0x1c, 0x5b, 0x3a, 0x24, 0xe0, 0x19, 0x1b, 0x1d, 0x42, 0xbf, 0x1c, 0x0a, 0x85, 0xa0,
+	0xe3, 0xec, 0x6e, 0xb9, 0x3d, 0x7b, 0x8e, 0x1e, 0x19, 0x16, 0x1d, 0x40, 0x57, 0x97, 0xa8, 0x14,
+	0x2f, 0xc5, 0x05, 0x9a, 0xb3, 0xca, 0xf0, 0x8f, 0x26, 0x74, 0xaa, 0x43, 0xea, 0x87, 0x60, 0xa9,
+	0x39, 0xe7, 0x79, 0xfa, 0x52, 0x4f, 0x83, 0x94, 0xde, 0x45, 0x82, 0x16, 0x39, 0x89, 0xa9, 0xab,
+	0xeb, 0x6e, 0xed, 0xf0, 0x58, 0xf3, 0xd8, 0xd2, 0x67, 0x6b, 0x7a, 0x50, 0xcc, 0x6d, 0xf4, 0x05,
+	0x6c, 0xe5, 0x19, 0xa3, 0x11, 0x65, 0x49, 0xed, 0xd7, 0xa1, 0xde, 0x16, 0x17, 0x19, 0xa3, 0x27,
+	0x96, 0x3d, 0x5b, 0xc3, 0xdd, 0x7c, 0x6e, 0xa2, 0x1f, 0xe0, 0x81, 0x7e, 0x2d, 0x8a, 0xc8, 0xfc,
+	0xe2, 0x44, 0xbc, 0xd0, 0xd3, 0xd9, 0xfe, 0x3f, 0x74, 0x8f, 0x3f, 0xa8, 0xe7, 0x4f, 0xab, 0x46,
+	0x5a, 0xf4, 0xa3, 0xd5, 0x9c, 0xad, 0xe1, 0x6d, 0xb9, 0x0c, 0x0e, 0x77, 0xa0, 0x57, 0x45, 0x5d,
+	0xf5, 0xdb, 0x77, 0x1b, 0x6d, 0xcf, 0x6f, 0x85, 0x29, 0x6c, 0xd5, 0x03, 0x79, 0x9f, 0xa1, 0xb0,
+	0x07, 0x5d, 0x97, 0x95, 0x29, 0x65, 0xca, 0x5d, 0xde, 0x3a, 0x84, 0x90, 0x9b, 0x65, 0x76, 0x10,
+	0x99, 0xef, 0xf0, 0x17, 0xe8, 0xd6, 0x42, 0x46, 0xc7, 0xe0, 0x49, 0x75, 0x9f, 0x53, 0xb3, 0x4b,
+	0x7f, 0x21, 0xaa, 0x9a, 0xec, 0xf0, 0x52, 0x6b, 0xb0, 0x95, 0x86, 0x8f, 0xc0, 0x33, 0x36, 0x6a,
+	0x41, 0xf3, 0xe2, 0xd4, 0x5f, 0x43, 0x6d, 0xd8, 0x78, 0x85, 0x2f, 0x4e, 0xfd, 0x46, 0x78, 0x02,
+	0xdb, 0x2b, 0xc9, 0x40, 0x3b, 0xe0, 0x31, 0x32, 0xad, 0x86, 0x81, 0x35, 0x6c, 0xd9, 0x4d, 0xff,
+	0x94, 0x77, 0xbf, 0xb2, 0x87, 0x07, 0x6f, 0xf7, 0xd3, 0x4c, 0x4d, 0x66, 0x57, 0x87, 0x31, 0x9f,
+	0x1e, 0x49, 0x9e, 0xf3, 0x17, 0x19, 0x3f, 0xaa, 0x8e, 0x36, 0xff, 0xba, 0x6a, 0x99, 0xda, 0xbc,
+	0xfc, 0x77, 0x00, 0x79, 0x51, 0xb8, 0xa0, 0xb5, 0x0a, 0x00, 0x00,
 }
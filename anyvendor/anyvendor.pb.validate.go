@@ -15,7 +15,7 @@ import (
 	"time"
 	"unicode/utf8"
 
-	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // ensure the imports are used
@@ -30,7 +30,7 @@ var (
 	_ = time.Duration(0)
 	_ = (*url.URL)(nil)
 	_ = (*mail.Address)(nil)
-	_ = ptypes.DynamicAny{}
+	_ = anypb.Any{}
 )
 
 // Validate checks the field values on Config with the rules defined in the
@@ -75,6 +75,56 @@ func (m *Config) Validate() error {
 		}
 	}
 
+	// no validation rules for ResolveImports
+
+	if v, ok := interface{}(m.GetValidateImports()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigValidationError{
+				field:  "ValidateImports",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetPatch()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigValidationError{
+				field:  "Patch",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetDescriptorSet()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigValidationError{
+				field:  "DescriptorSet",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetBreakingChanges()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigValidationError{
+				field:  "BreakingChanges",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for SkipLicenses
+
+	// no validation rules for ProvenanceHeaders
+
+	// no validation rules for SkipMetadata
+
+	// no validation rules for SkipWellKnownImports
+
 	return nil
 }
 
@@ -132,22 +182,22 @@ var _ interface {
 	ErrorName() string
 } = ConfigValidationError{}
 
-// Validate checks the field values on FactorySettings with the rules defined
+// Validate checks the field values on BreakingChanges with the rules defined
 // in the proto definition for this message. If any rules are violated, an
 // error is returned.
-func (m *FactorySettings) Validate() error {
+func (m *BreakingChanges) Validate() error {
 	if m == nil {
 		return nil
 	}
 
-	// no validation rules for Cwd
+	// no validation rules for Strict
 
 	return nil
 }
 
-// FactorySettingsValidationError is the validation error returned by
-// FactorySettings.Validate if the designated constraints aren't met.
-type FactorySettingsValidationError struct {
+// BreakingChangesValidationError is the validation error returned by
+// BreakingChanges.Validate if the designated constraints aren't met.
+type BreakingChangesValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -155,22 +205,22 @@ type FactorySettingsValidationError struct {
 }
 
 // Field function returns field value.
-func (e FactorySettingsValidationError) Field() string { return e.field }
+func (e BreakingChangesValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e FactorySettingsValidationError) Reason() string { return e.reason }
+func (e BreakingChangesValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e FactorySettingsValidationError) Cause() error { return e.cause }
+func (e BreakingChangesValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e FactorySettingsValidationError) Key() bool { return e.key }
+func (e BreakingChangesValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e FactorySettingsValidationError) ErrorName() string { return "FactorySettingsValidationError" }
+func (e BreakingChangesValidationError) ErrorName() string { return "BreakingChangesValidationError" }
 
 // Error satisfies the builtin error interface
-func (e FactorySettingsValidationError) Error() string {
+func (e BreakingChangesValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -182,14 +232,14 @@ func (e FactorySettingsValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sFactorySettings.%s: %s%s",
+		"invalid %sBreakingChanges.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = FactorySettingsValidationError{}
+var _ error = BreakingChangesValidationError{}
 
 var _ interface {
 	Field() string
@@ -197,43 +247,98 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = FactorySettingsValidationError{}
+} = BreakingChangesValidationError{}
 
-// Validate checks the field values on Import with the rules defined in the
-// proto definition for this message. If any rules are violated, an error is returned.
-func (m *Import) Validate() error {
+// Validate checks the field values on DescriptorSet with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *DescriptorSet) Validate() error {
 	if m == nil {
 		return nil
 	}
 
-	switch m.ImportType.(type) {
+	if utf8.RuneCountInString(m.GetPath()) < 1 {
+		return DescriptorSetValidationError{
+			field:  "Path",
+			reason: "value length must be at least 1 runes",
+		}
+	}
 
-	case *Import_GoMod:
+	// no validation rules for IncludeSourceInfo
 
-		if v, ok := interface{}(m.GetGoMod()).(interface{ Validate() error }); ok {
-			if err := v.Validate(); err != nil {
-				return ImportValidationError{
-					field:  "GoMod",
-					reason: "embedded message failed validation",
-					cause:  err,
-				}
-			}
-		}
+	// no validation rules for IncludeImports
 
-	default:
-		return ImportValidationError{
-			field:  "ImportType",
-			reason: "value is required",
-		}
+	return nil
+}
+
+// DescriptorSetValidationError is the validation error returned by
+// DescriptorSet.Validate if the designated constraints aren't met.
+type DescriptorSetValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DescriptorSetValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DescriptorSetValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DescriptorSetValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DescriptorSetValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DescriptorSetValidationError) ErrorName() string { return "DescriptorSetValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DescriptorSetValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDescriptorSet.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
 
+var _ error = DescriptorSetValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DescriptorSetValidationError{}
+
+// Validate checks the field values on ImportValidation with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *ImportValidation) Validate() error {
+	if m == nil {
+		return nil
 	}
 
 	return nil
 }
 
-// ImportValidationError is the validation error returned by Import.Validate if
-// the designated constraints aren't met.
-type ImportValidationError struct {
+// ImportValidationValidationError is the validation error returned by
+// ImportValidation.Validate if the designated constraints aren't met.
+type ImportValidationValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -241,22 +346,22 @@ type ImportValidationError struct {
 }
 
 // Field function returns field value.
-func (e ImportValidationError) Field() string { return e.field }
+func (e ImportValidationValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ImportValidationError) Reason() string { return e.reason }
+func (e ImportValidationValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ImportValidationError) Cause() error { return e.cause }
+func (e ImportValidationValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ImportValidationError) Key() bool { return e.key }
+func (e ImportValidationValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ImportValidationError) ErrorName() string { return "ImportValidationError" }
+func (e ImportValidationValidationError) ErrorName() string { return "ImportValidationValidationError" }
 
 // Error satisfies the builtin error interface
-func (e ImportValidationError) Error() string {
+func (e ImportValidationValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -268,14 +373,14 @@ func (e ImportValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sImport.%s: %s%s",
+		"invalid %sImportValidation.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ImportValidationError{}
+var _ error = ImportValidationValidationError{}
 
 var _ interface {
 	Field() string
@@ -283,28 +388,63 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ImportValidationError{}
+} = ImportValidationValidationError{}
 
-// Validate checks the field values on Local with the rules defined in the
+// Validate checks the field values on Patch with the rules defined in the
 // proto definition for this message. If any rules are violated, an error is returned.
-func (m *Local) Validate() error {
+func (m *Patch) Validate() error {
 	if m == nil {
 		return nil
 	}
 
-	if len(m.GetPatterns()) < 1 {
-		return LocalValidationError{
-			field:  "Patterns",
-			reason: "value must contain at least 1 item(s)",
+	for idx, item := range m.GetGoPackageRules() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return PatchValidationError{
+					field:  fmt.Sprintf("GoPackageRules[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if v, ok := interface{}(m.GetGoPackageMapping()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return PatchValidationError{
+				field:  "GoPackageMapping",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetImportRewrites() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return PatchValidationError{
+					field:  fmt.Sprintf("ImportRewrites[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
 		}
+
 	}
 
+	// no validation rules for RewriteLocal
+
 	return nil
 }
 
-// LocalValidationError is the validation error returned by Local.Validate if
+// PatchValidationError is the validation error returned by Patch.Validate if
 // the designated constraints aren't met.
-type LocalValidationError struct {
+type PatchValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -312,22 +452,22 @@ type LocalValidationError struct {
 }
 
 // Field function returns field value.
-func (e LocalValidationError) Field() string { return e.field }
+func (e PatchValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e LocalValidationError) Reason() string { return e.reason }
+func (e PatchValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e LocalValidationError) Cause() error { return e.cause }
+func (e PatchValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e LocalValidationError) Key() bool { return e.key }
+func (e PatchValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e LocalValidationError) ErrorName() string { return "LocalValidationError" }
+func (e PatchValidationError) ErrorName() string { return "PatchValidationError" }
 
 // Error satisfies the builtin error interface
-func (e LocalValidationError) Error() string {
+func (e PatchValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -339,14 +479,14 @@ func (e LocalValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sLocal.%s: %s%s",
+		"invalid %sPatch.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = LocalValidationError{}
+var _ error = PatchValidationError{}
 
 var _ interface {
 	Field() string
@@ -354,26 +494,26 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = LocalValidationError{}
+} = PatchValidationError{}
 
-// Validate checks the field values on GoModImport with the rules defined in
+// Validate checks the field values on ImportRewrite with the rules defined in
 // the proto definition for this message. If any rules are violated, an error
 // is returned.
-func (m *GoModImport) Validate() error {
+func (m *ImportRewrite) Validate() error {
 	if m == nil {
 		return nil
 	}
 
-	if len(m.GetPatterns()) < 1 {
-		return GoModImportValidationError{
-			field:  "Patterns",
-			reason: "value must contain at least 1 item(s)",
+	if utf8.RuneCountInString(m.GetFrom()) < 1 {
+		return ImportRewriteValidationError{
+			field:  "From",
+			reason: "value length must be at least 1 runes",
 		}
 	}
 
-	if utf8.RuneCountInString(m.GetPackage()) < 1 {
-		return GoModImportValidationError{
-			field:  "Package",
+	if utf8.RuneCountInString(m.GetTo()) < 1 {
+		return ImportRewriteValidationError{
+			field:  "To",
 			reason: "value length must be at least 1 runes",
 		}
 	}
@@ -381,9 +521,9 @@ func (m *GoModImport) Validate() error {
 	return nil
 }
 
-// GoModImportValidationError is the validation error returned by
-// GoModImport.Validate if the designated constraints aren't met.
-type GoModImportValidationError struct {
+// ImportRewriteValidationError is the validation error returned by
+// ImportRewrite.Validate if the designated constraints aren't met.
+type ImportRewriteValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -391,22 +531,22 @@ type GoModImportValidationError struct {
 }
 
 // Field function returns field value.
-func (e GoModImportValidationError) Field() string { return e.field }
+func (e ImportRewriteValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e GoModImportValidationError) Reason() string { return e.reason }
+func (e ImportRewriteValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e GoModImportValidationError) Cause() error { return e.cause }
+func (e ImportRewriteValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e GoModImportValidationError) Key() bool { return e.key }
+func (e ImportRewriteValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e GoModImportValidationError) ErrorName() string { return "GoModImportValidationError" }
+func (e ImportRewriteValidationError) ErrorName() string { return "ImportRewriteValidationError" }
 
 // Error satisfies the builtin error interface
-func (e GoModImportValidationError) Error() string {
+func (e ImportRewriteValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -418,14 +558,14 @@ func (e GoModImportValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sGoModImport.%s: %s%s",
+		"invalid %sImportRewrite.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = GoModImportValidationError{}
+var _ error = ImportRewriteValidationError{}
 
 var _ interface {
 	Field() string
@@ -433,4 +573,959 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = GoModImportValidationError{}
+} = ImportRewriteValidationError{}
+
+// Validate checks the field values on GoPackageMapping with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *GoPackageMapping) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetPath()) < 1 {
+		return GoPackageMappingValidationError{
+			field:  "Path",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for BufPath
+
+	// no validation rules for ModuleRelative
+
+	return nil
+}
+
+// GoPackageMappingValidationError is the validation error returned by
+// GoPackageMapping.Validate if the designated constraints aren't met.
+type GoPackageMappingValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GoPackageMappingValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GoPackageMappingValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GoPackageMappingValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GoPackageMappingValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GoPackageMappingValidationError) ErrorName() string { return "GoPackageMappingValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GoPackageMappingValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGoPackageMapping.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GoPackageMappingValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GoPackageMappingValidationError{}
+
+// Validate checks the field values on GoPackageRule with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *GoPackageRule) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetPattern()) < 1 {
+		return GoPackageRuleValidationError{
+			field:  "Pattern",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetGoPackage()) < 1 {
+		return GoPackageRuleValidationError{
+			field:  "GoPackage",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	return nil
+}
+
+// GoPackageRuleValidationError is the validation error returned by
+// GoPackageRule.Validate if the designated constraints aren't met.
+type GoPackageRuleValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GoPackageRuleValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GoPackageRuleValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GoPackageRuleValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GoPackageRuleValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GoPackageRuleValidationError) ErrorName() string { return "GoPackageRuleValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GoPackageRuleValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGoPackageRule.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GoPackageRuleValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GoPackageRuleValidationError{}
+
+// Validate checks the field values on FactorySettings with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *FactorySettings) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Cwd
+
+	if v, ok := interface{}(m.GetPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return FactorySettingsValidationError{
+				field:  "Policy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// FactorySettingsValidationError is the validation error returned by
+// FactorySettings.Validate if the designated constraints aren't met.
+type FactorySettingsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e FactorySettingsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e FactorySettingsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e FactorySettingsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e FactorySettingsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e FactorySettingsValidationError) ErrorName() string { return "FactorySettingsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e FactorySettingsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sFactorySettings.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = FactorySettingsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = FactorySettingsValidationError{}
+
+// Validate checks the field values on Policy with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *Policy) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// PolicyValidationError is the validation error returned by Policy.Validate if
+// the designated constraints aren't met.
+type PolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PolicyValidationError) ErrorName() string { return "PolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PolicyValidationError{}
+
+// Validate checks the field values on Import with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *Import) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	switch m.ImportType.(type) {
+
+	case *Import_GoMod:
+
+		if v, ok := interface{}(m.GetGoMod()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImportValidationError{
+					field:  "GoMod",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	default:
+		return ImportValidationError{
+			field:  "ImportType",
+			reason: "value is required",
+		}
+
+	}
+
+	return nil
+}
+
+// ImportValidationError is the validation error returned by Import.Validate if
+// the designated constraints aren't met.
+type ImportValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportValidationError) ErrorName() string { return "ImportValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImportValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImport.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportValidationError{}
+
+// Validate checks the field values on Local with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *Local) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if len(m.GetPatterns()) < 1 {
+		return LocalValidationError{
+			field:  "Patterns",
+			reason: "value must contain at least 1 item(s)",
+		}
+	}
+
+	return nil
+}
+
+// LocalValidationError is the validation error returned by Local.Validate if
+// the designated constraints aren't met.
+type LocalValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LocalValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LocalValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LocalValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LocalValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LocalValidationError) ErrorName() string { return "LocalValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LocalValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLocal.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LocalValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LocalValidationError{}
+
+// Validate checks the field values on GoModImport with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *GoModImport) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if len(m.GetPatterns()) < 1 {
+		return GoModImportValidationError{
+			field:  "Patterns",
+			reason: "value must contain at least 1 item(s)",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetPackage()) < 1 {
+		return GoModImportValidationError{
+			field:  "Package",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	for idx, item := range m.GetTransforms() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GoModImportValidationError{
+					field:  fmt.Sprintf("Transforms[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if v, ok := interface{}(m.GetShade()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GoModImportValidationError{
+				field:  "Shade",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// GoModImportValidationError is the validation error returned by
+// GoModImport.Validate if the designated constraints aren't met.
+type GoModImportValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GoModImportValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GoModImportValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GoModImportValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GoModImportValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GoModImportValidationError) ErrorName() string { return "GoModImportValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GoModImportValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGoModImport.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GoModImportValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GoModImportValidationError{}
+
+// Validate checks the field values on Shade with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *Shade) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetPackagePrefix()) < 1 {
+		return ShadeValidationError{
+			field:  "PackagePrefix",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetPathPrefix()) < 1 {
+		return ShadeValidationError{
+			field:  "PathPrefix",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	return nil
+}
+
+// ShadeValidationError is the validation error returned by Shade.Validate if
+// the designated constraints aren't met.
+type ShadeValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ShadeValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ShadeValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ShadeValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ShadeValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ShadeValidationError) ErrorName() string { return "ShadeValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ShadeValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sShade.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ShadeValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ShadeValidationError{}
+
+// Validate checks the field values on Transform with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *Transform) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	switch m.TransformType.(type) {
+
+	case *Transform_RegexReplace:
+
+		if v, ok := interface{}(m.GetRegexReplace()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TransformValidationError{
+					field:  "RegexReplace",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *Transform_LineEndings:
+
+		if v, ok := interface{}(m.GetLineEndings()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TransformValidationError{
+					field:  "LineEndings",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *Transform_StripProtoOptions:
+
+		if v, ok := interface{}(m.GetStripProtoOptions()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TransformValidationError{
+					field:  "StripProtoOptions",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	default:
+		return TransformValidationError{
+			field:  "TransformType",
+			reason: "value is required",
+		}
+
+	}
+
+	return nil
+}
+
+// TransformValidationError is the validation error returned by
+// Transform.Validate if the designated constraints aren't met.
+type TransformValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TransformValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TransformValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TransformValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TransformValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TransformValidationError) ErrorName() string { return "TransformValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TransformValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTransform.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TransformValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TransformValidationError{}
+
+// Validate checks the field values on RegexReplace with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *RegexReplace) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetPattern()) < 1 {
+		return RegexReplaceValidationError{
+			field:  "Pattern",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for Replacement
+
+	// no validation rules for Path
+
+	return nil
+}
+
+// RegexReplaceValidationError is the validation error returned by
+// RegexReplace.Validate if the designated constraints aren't met.
+type RegexReplaceValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegexReplaceValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegexReplaceValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegexReplaceValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegexReplaceValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegexReplaceValidationError) ErrorName() string { return "RegexReplaceValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RegexReplaceValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegexReplace.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegexReplaceValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegexReplaceValidationError{}
+
+// Validate checks the field values on LineEndings with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *LineEndings) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Style
+
+	return nil
+}
+
+// LineEndingsValidationError is the validation error returned by
+// LineEndings.Validate if the designated constraints aren't met.
+type LineEndingsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LineEndingsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LineEndingsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LineEndingsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LineEndingsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LineEndingsValidationError) ErrorName() string { return "LineEndingsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LineEndingsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLineEndings.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LineEndingsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LineEndingsValidationError{}
+
+// Validate checks the field values on StripProtoOptions with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *StripProtoOptions) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// StripProtoOptionsValidationError is the validation error returned by
+// StripProtoOptions.Validate if the designated constraints aren't met.
+type StripProtoOptionsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e StripProtoOptionsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e StripProtoOptionsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e StripProtoOptionsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e StripProtoOptionsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e StripProtoOptionsValidationError) ErrorName() string {
+	return "StripProtoOptionsValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e StripProtoOptionsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sStripProtoOptions.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = StripProtoOptionsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = StripProtoOptionsValidationError{}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"google.golang.org/protobuf/encoding/prototext"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	UnknownFormatError = eris.New("unknown config format, expected .yaml, .yml, .json, .textproto, .txtpb, .pbtxt or .prototext")
+	IncludeCycleError  = eris.New("config includes itself")
+)
+
+// Error is an error in a config file, along with where in the file it was found.
+type Error struct {
+	File string
+	// 1 based, zero when the position is not known
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Err.Error())
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err.Error())
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+/*
+LoadConfig reads the config file at path, merges in the files it includes, and validates it. The format is chosen by the extension of the file:
+YAML (.yaml, .yml) and JSON (.json) files use the protojson field names shown in the README, and prototext files
+(.textproto, .txtpb, .pbtxt, .prototext) use the proto field names. Errors are returned as an *Error, with the
+line and column of the offending field when it is known.
+
+Environment variables referenced by the values of the file, as ${VAR} or ${VAR:-default}, are interpolated before
+validation, and the values of those referenced as ${secret:VAR} are registered with the redact package.
+*/
+func LoadConfig(path string) (*anyvendor.Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, content)
+}
+
+/*
+Parse parses and validates the contents of a config file, merging in the files it includes. The filename is used
+to choose the format, and to resolve the includes against.
+*/
+func Parse(filename string, content []byte) (*anyvendor.Config, error) {
+	cfg, root, err := parse(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	merged := cfg
+	if len(cfg.GetIncludes()) > 0 {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			return nil, err
+		}
+		if merged, err = resolveIncludes(filename, cfg, []string{abs}, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+	if err := merged.Validate(); err != nil {
+		configErr := &Error{File: filename, Err: err}
+		if merged != cfg {
+			/*
+				the fields of the merged config may come from any of the files, and its lists are offset by those of
+				the included files, so the error is only positioned when the file reproduces it on its own
+			*/
+			own := protov1.Clone(cfg).(*anyvendor.Config)
+			own.Includes = nil
+			if ownErr := own.Validate(); ownErr != nil && validationSignature(ownErr) == validationSignature(err) {
+				configErr.Err = ownErr
+			} else {
+				root = nil
+			}
+		}
+		if root != nil {
+			configErr.Line, configErr.Column = validationPosition(root, protov1.MessageV2(cfg).ProtoReflect().Descriptor(), configErr.Err)
+		}
+		return nil, configErr
+	}
+	return merged, nil
+}
+
+// parse the contents of a config file without validating it, returning the root node of YAML and JSON files
+func parse(filename string, content []byte) (*anyvendor.Config, *yaml.Node, error) {
+	cfg := &anyvendor.Config{}
+	// the generated config predates the v2 proto api
+	msg := protov1.MessageV2(cfg)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml", ".json":
+		// JSON is parsed as YAML, which it is a subset of, to find the position of every field
+		root, err := parseYAML(filename, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalYAML(filename, root, msg); err != nil {
+			return nil, nil, err
+		}
+		return cfg, root, nil
+	case ".textproto", ".txtpb", ".pbtxt", ".prototext":
+		// prototext errors include the line and column
+		if err := prototext.Unmarshal(content, msg); err != nil {
+			return nil, nil, &Error{File: filename, Err: err}
+		}
+		// only string fields can be interpolated, as the value of any other field must parse as its type
+		if err := interpolateMessage(msg.ProtoReflect()); err != nil {
+			return nil, nil, &Error{File: filename, Err: err}
+		}
+		return cfg, nil, nil
+	}
+	return nil, nil, eris.Wrapf(UnknownFormatError, "%s", filename)
+}
+
+/*
+merge the includes of the config beneath it, the stack holds the absolute paths of the files including it. A file
+included more than once, such as the shared base of two included files, is only merged the first time, which is
+tracked by the absolute paths in mergedFiles.
+*/
+func resolveIncludes(filename string, cfg *anyvendor.Config, stack []string, mergedFiles map[string]bool) (*anyvendor.Config, error) {
+	merged := &anyvendor.Config{}
+	for _, include := range cfg.GetIncludes() {
+		path := include
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(filename), path)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, including := range stack {
+			if including == abs {
+				return nil, &Error{
+					File: filename,
+					Err:  eris.Wrapf(IncludeCycleError, "%s", strings.Join(append(stack, abs), " -> ")),
+				}
+			}
+		}
+		if mergedFiles[abs] {
+			continue
+		}
+		mergedFiles[abs] = true
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &Error{File: filename, Err: err}
+		}
+		included, _, err := parse(path, content)
+		if err != nil {
+			return nil, err
+		}
+		if included, err = resolveIncludes(path, included, append(stack[:len(stack):len(stack)], abs), mergedFiles); err != nil {
+			return nil, err
+		}
+		merged = Merge(merged, included)
+	}
+	overlay := protov1.Clone(cfg).(*anyvendor.Config)
+	overlay.Includes = nil
+	return Merge(merged, overlay), nil
+}
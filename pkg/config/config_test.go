@@ -0,0 +1,106 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/config"
+)
+
+var _ = Describe("LoadConfig", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	load := func(name, content string) (*anyvendor.Config, error) {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return config.LoadConfig(path)
+	}
+	expectError := func(err error, line, column int, message string) {
+		Expect(err).To(HaveOccurred())
+		configErr, ok := err.(*config.Error)
+		Expect(ok).To(BeTrue(), err.Error())
+		Expect(configErr.Line).To(Equal(line), err.Error())
+		Expect(configErr.Column).To(Equal(column), err.Error())
+		Expect(err.Error()).To(ContainSubstring(message))
+	}
+
+	It("loads yaml", func() {
+		cfg, err := load("anyvendor.yaml", `
+local:
+  patterns:
+  - api/**/*.proto
+resolveImports: true
+imports:
+- goMod:
+    package: github.com/envoyproxy/protoc-gen-validate
+    patterns:
+    - validate/*.proto
+    transforms:
+    - lineEndings:
+        style: CRLF
+patch:
+  go_package_rules:
+  - pattern: "**/*.proto"
+    goPackage: "{dir}"
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetLocal().GetPatterns()).To(Equal([]string{"api/**/*.proto"}))
+		Expect(cfg.GetResolveImports()).To(BeTrue())
+		Expect(cfg.GetImports()).To(HaveLen(1))
+		Expect(cfg.GetImports()[0].GetGoMod().GetPackage()).To(Equal("github.com/envoyproxy/protoc-gen-validate"))
+		Expect(cfg.GetImports()[0].GetGoMod().GetTransforms()[0].GetLineEndings().GetStyle()).
+			To(Equal(anyvendor.LineEndings_CRLF))
+		Expect(cfg.GetPatch().GetGoPackageRules()[0].GetGoPackage()).To(Equal("{dir}"))
+	})
+	It("loads json and prototext", func() {
+		cfg, err := load("anyvendor.json", `{"imports": [{"goMod": {"package": "github.com/foo/bar", "patterns": ["*.proto"]}}]}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetImports()[0].GetGoMod().GetPatterns()).To(Equal([]string{"*.proto"}))
+
+		cfg, err = load("anyvendor.textproto", `imports { go_mod { package: "github.com/foo/bar" patterns: "*.proto" } }`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetImports()[0].GetGoMod().GetPackage()).To(Equal("github.com/foo/bar"))
+	})
+	It("loads an empty config", func() {
+		cfg, err := load("anyvendor.yaml", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetImports()).To(BeEmpty())
+	})
+	It("reports the position of unknown fields and invalid values", func() {
+		_, err := load("anyvendor.yaml", "imports:\n- goMod:\n    package: github.com/foo/bar\n    pattern: [\"*.proto\"]\n")
+		expectError(err, 4, 5, `unknown field "pattern" in GoModImport`)
+
+		_, err = load("anyvendor.yaml", "resolveImports: maybe\n")
+		expectError(err, 1, 17, `expected a bool for resolve_imports, got "maybe"`)
+
+		_, err = load("anyvendor.json", "{\n  \"imports\": {\"goMod\": {}}\n}")
+		expectError(err, 2, 14, "expected a list for imports")
+	})
+	It("reports the position of validation errors", func() {
+		_, err := load("anyvendor.yaml", "imports:\n- goMod:\n    package: github.com/foo/bar\n    patterns: []\n")
+		expectError(err, 4, 15, "GoModImport.Patterns")
+
+		_, err = load("anyvendor.yaml", "imports:\n- goMod:\n    package: github.com/foo/bar\n    patterns: [\"*.proto\"]\n- {}\n")
+		expectError(err, 5, 3, "Import.ImportType")
+	})
+	It("reports syntax errors", func() {
+		_, err := load("anyvendor.yaml", "imports:\n  - goMod: {\n")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(filepath.Join(dir, "anyvendor.yaml") + ":"))
+
+		_, err = load("anyvendor.textproto", "imports { go_mod { pattern: \"*.proto\" } }")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("line 1"))
+
+		_, err = load("anyvendor.toml", "")
+		Expect(eris.Is(err, config.UnknownFormatError)).To(BeTrue())
+	})
+})
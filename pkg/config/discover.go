@@ -0,0 +1,93 @@
+package config
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-zglob"
+	"github.com/solo-io/anyvendor/anyvendor"
+)
+
+// ConfigName is the name, without its extension, of the config files found by Discover.
+const ConfigName = "anyvendor"
+
+// returns true if the name is ConfigName with the extension of a supported format
+func isConfigFile(name string) bool {
+	ext := filepath.Ext(name)
+	if strings.TrimSuffix(name, ext) != ConfigName {
+		return false
+	}
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml", ".json", ".textproto", ".txtpb", ".pbtxt", ".prototext":
+		return true
+	}
+	return false
+}
+
+// Discover returns the paths of the config files beneath root, such as anyvendor.yaml or anyvendor.textproto,
+// sorted so a config comes before those in its subdirectories. The skip patterns are globs, like the skip patterns
+// of the factory settings, matched against paths relative to root; a directory is matched with a trailing slash, so
+// **/node_modules/** skips every node_modules directory. Hidden directories and vendor_any directories are always
+// skipped.
+func Discover(root string, skipPatterns []string) ([]string, error) {
+	skipped := func(relative string) (bool, error) {
+		for _, pattern := range skipPatterns {
+			matched, err := zglob.Match(pattern, relative)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	var configs []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+		if entry.IsDir() {
+			if relative == "." {
+				return nil
+			}
+			if strings.HasPrefix(entry.Name(), ".") || entry.Name() == anyvendor.DefaultDepDir {
+				return filepath.SkipDir
+			}
+			skip, err := skipped(relative + "/")
+			if err != nil {
+				return err
+			}
+			if skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isConfigFile(entry.Name()) {
+			return nil
+		}
+		skip, err := skipped(relative)
+		if err != nil {
+			return err
+		}
+		if !skip {
+			configs = append(configs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return filepath.Dir(configs[i]) < filepath.Dir(configs[j])
+	})
+	return configs, nil
+}
@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/pkg/config"
+)
+
+var _ = Describe("Discover", func() {
+	var root string
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		for _, name := range []string{
+			"anyvendor.yaml",
+			"api/anyvendor.textproto",
+			"api/v1/anyvendor.json",
+			"aa/anyvendor.yml",
+			"api/anyvendor.yaml.bak",
+			"api/.anyvendor.json",
+			"docs/other.yaml",
+			"web/node_modules/pkg/anyvendor.yaml",
+			"vendor_any/github.com/example/anyvendor.yaml",
+			".github/anyvendor.yaml",
+			"testdata/anyvendor.yaml",
+		} {
+			path := filepath.Join(root, name)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(os.WriteFile(path, nil, 0644)).To(Succeed())
+		}
+	})
+
+	It("finds the configs beneath the root, parents first", func() {
+		configs, err := config.Discover(root, []string{"**/node_modules/**"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(Equal([]string{
+			filepath.Join(root, "anyvendor.yaml"),
+			filepath.Join(root, "aa/anyvendor.yml"),
+			filepath.Join(root, "api/anyvendor.textproto"),
+			filepath.Join(root, "api/v1/anyvendor.json"),
+			filepath.Join(root, "testdata/anyvendor.yaml"),
+		}))
+	})
+
+	It("skips directories and configs matching the skip patterns", func() {
+		configs, err := config.Discover(root, []string{"**/node_modules/**", "testdata/**", "api/v1/*.json"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(Equal([]string{
+			filepath.Join(root, "anyvendor.yaml"),
+			filepath.Join(root, "aa/anyvendor.yml"),
+			filepath.Join(root, "api/anyvendor.textproto"),
+		}))
+	})
+
+	It("errors if the root does not exist", func() {
+		_, err := config.Discover(filepath.Join(root, "missing"), nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	UnsetVariableError        = eris.New("variable is not set")
+	UnterminatedVariableError = eris.New("unterminated variable reference")
+)
+
+// the prefix of a reference to a variable whose value is secret, e.g. ${secret:GIT_TOKEN}
+const secretPrefix = "secret:"
+
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+/*
+interpolate the environment variables referenced by the value. ${VAR} is replaced with the value of VAR, which must
+be set, and ${VAR:-default} with the default when VAR is unset or empty. Values referenced as ${secret:VAR} are
+registered with the redact package. $$ is replaced with $, and any other $, such as the $1 or ${1} of a regex
+replacement, is left as it is.
+*/
+func interpolate(value string) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			result.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			result.WriteByte('$')
+			i++
+			continue
+		case '{':
+		default:
+			result.WriteByte('$')
+			continue
+		}
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", eris.Wrapf(UnterminatedVariableError, "%q", value[i:])
+		}
+		reference := value[i+2 : i+end]
+		secret := strings.HasPrefix(reference, secretPrefix)
+		name, fallback, hasFallback := strings.Cut(strings.TrimPrefix(reference, secretPrefix), ":-")
+		if !variableNameRegex.MatchString(name) {
+			// not a variable reference, such as a regex replacement
+			result.WriteString(value[i : i+end+1])
+			i += end
+			continue
+		}
+		resolved, ok := os.LookupEnv(name)
+		if hasFallback && resolved == "" {
+			resolved, ok = fallback, true
+		}
+		if !ok {
+			return "", eris.Wrapf(UnsetVariableError, "%s", name)
+		}
+		if secret {
+			redact.Register(resolved)
+		}
+		result.WriteString(resolved)
+		i += end
+	}
+	return result.String(), nil
+}
+
+// interpolate every string field of the message, and of the messages within it
+func interpolateMessage(msg protoreflect.Message) error {
+	var fields []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		switch {
+		case fd.IsList():
+			list := msg.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				value, err := interpolateValue(fd, list.Get(i))
+				if err != nil {
+					return err
+				}
+				list.Set(i, value)
+			}
+		case fd.IsMap():
+			entries := msg.Mutable(fd).Map()
+			var keys []protoreflect.MapKey
+			entries.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, key)
+				return true
+			})
+			for _, key := range keys {
+				value, err := interpolateValue(fd.MapValue(), entries.Get(key))
+				if err != nil {
+					return err
+				}
+				entries.Set(key, value)
+			}
+		default:
+			value, err := interpolateValue(fd, msg.Get(fd))
+			if err != nil {
+				return err
+			}
+			msg.Set(fd, value)
+		}
+	}
+	return nil
+}
+
+func interpolateValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return value, interpolateMessage(value.Message())
+	case protoreflect.StringKind:
+		interpolated, err := interpolate(value.String())
+		return protoreflect.ValueOfString(interpolated), err
+	}
+	return value, nil
+}
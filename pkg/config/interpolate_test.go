@@ -0,0 +1,111 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/pkg/config"
+	"github.com/solo-io/anyvendor/pkg/redact"
+)
+
+var _ = Describe("interpolation", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		GinkgoT().Setenv("ANYVENDOR_CWD", "services/billing")
+		GinkgoT().Setenv("ANYVENDOR_RESOLVE", "true")
+		GinkgoT().Setenv("ANYVENDOR_EMPTY", "")
+		GinkgoT().Setenv("ANYVENDOR_TOKEN", "hunter2")
+	})
+	AfterEach(func() {
+		redact.Reset()
+	})
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	It("interpolates environment variables in yaml", func() {
+		cfg, err := config.LoadConfig(write("anyvendor.yaml", `
+settings:
+  cwd: ${ANYVENDOR_CWD}
+resolveImports: ${ANYVENDOR_RESOLVE}
+local:
+  patterns:
+  - ${ANYVENDOR_EMPTY:-api}/**/*.proto
+  - ${ANYVENDOR_UNSET:-protos}/$${HOME}/*.proto
+imports:
+- goMod:
+    package: github.com/solo-io/solo-kit
+    patterns:
+    - api/**/*.proto
+    transforms:
+    - regexReplace:
+        pattern: ^api/(v\d+)/
+        replacement: ${1}/$1/
+        path: true
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetSettings().GetCwd()).To(Equal("services/billing"))
+		Expect(cfg.GetResolveImports()).To(BeTrue())
+		Expect(cfg.GetLocal().GetPatterns()).To(Equal([]string{"api/**/*.proto", "protos/${HOME}/*.proto"}))
+		Expect(cfg.GetImports()[0].GetGoMod().GetTransforms()[0].GetRegexReplace().GetReplacement()).
+			To(Equal("${1}/$1/"))
+	})
+
+	It("interpolates the string fields of prototext", func() {
+		cfg, err := config.LoadConfig(write("anyvendor.textproto", `
+settings { cwd: "${ANYVENDOR_CWD}" }
+local { patterns: "${ANYVENDOR_UNSET:-api}/**/*.proto" }
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetSettings().GetCwd()).To(Equal("services/billing"))
+		Expect(cfg.GetLocal().GetPatterns()).To(Equal([]string{"api/**/*.proto"}))
+	})
+
+	It("interpolates includes", func() {
+		write("shared.yaml", "resolveImports: true\n")
+		GinkgoT().Setenv("ANYVENDOR_SHARED", "shared.yaml")
+		cfg, err := config.LoadConfig(write("anyvendor.yaml", "includes:\n- ${ANYVENDOR_SHARED}\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetResolveImports()).To(BeTrue())
+	})
+
+	It("errors on unset variables, at their position", func() {
+		_, err := config.LoadConfig(write("anyvendor.yaml", "settings:\n  cwd: ${ANYVENDOR_UNSET}\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(eris.Is(err, config.UnsetVariableError)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("anyvendor.yaml:2:8: ANYVENDOR_UNSET: variable is not set"))
+
+		_, err = config.LoadConfig(write("anyvendor.textproto", `settings { cwd: "${ANYVENDOR_CWD" }`))
+		Expect(err).To(HaveOccurred())
+		Expect(eris.Is(err, config.UnterminatedVariableError)).To(BeTrue())
+	})
+
+	It("registers secrets for redaction", func() {
+		cfg, err := config.LoadConfig(write("anyvendor.yaml", `
+local:
+  patterns:
+  - ${secret:ANYVENDOR_TOKEN}/*.proto
+  - ${secret:ANYVENDOR_UNSET:-fallback-secret}/*.proto
+  - ${ANYVENDOR_CWD}/*.proto
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetLocal().GetPatterns()[0]).To(Equal("hunter2/*.proto"))
+		Expect(redact.String("hunter2 fallback-secret services/billing")).
+			To(Equal("[REDACTED] [REDACTED] services/billing"))
+	})
+
+	It("quotes values as written in type errors", func() {
+		_, err := config.LoadConfig(write("anyvendor.yaml", "resolveImports: ${secret:ANYVENDOR_TOKEN}\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`got "${secret:ANYVENDOR_TOKEN}"`))
+		Expect(err.Error()).NotTo(ContainSubstring("hunter2"))
+	})
+})
@@ -0,0 +1,63 @@
+package config
+
+import (
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/solo-io/anyvendor/anyvendor"
+)
+
+/*
+Merge returns a copy of the base config with the overlay merged on top of it. Imports of a go mod package which the
+base already imports replace its patterns, patches, transforms and shade when they are set in the overlay, and other
+imports are appended. Every other list is appended, and every other field of the overlay replaces the base value
+when it is set. Neither config is modified.
+*/
+func Merge(base, overlay *anyvendor.Config) *anyvendor.Config {
+	merged := &anyvendor.Config{}
+	if base != nil {
+		merged = protov1.Clone(base).(*anyvendor.Config)
+	}
+	if overlay == nil {
+		return merged
+	}
+	rest := protov1.Clone(overlay).(*anyvendor.Config)
+	imports := rest.GetImports()
+	rest.Imports = nil
+	protov1.Merge(merged, rest)
+
+	for _, imp := range imports {
+		existing := findGoModImport(merged.GetImports(), imp.GetGoMod().GetPackage())
+		if imp.GetGoMod() == nil || existing == nil {
+			merged.Imports = append(merged.Imports, imp)
+			continue
+		}
+		overlayGoModImport(existing, imp.GetGoMod())
+	}
+	return merged
+}
+
+func findGoModImport(imports []*anyvendor.Import, pkg string) *anyvendor.GoModImport {
+	if pkg == "" {
+		return nil
+	}
+	for _, imp := range imports {
+		if imp.GetGoMod().GetPackage() == pkg {
+			return imp.GetGoMod()
+		}
+	}
+	return nil
+}
+
+func overlayGoModImport(existing, overlay *anyvendor.GoModImport) {
+	if len(overlay.GetPatterns()) > 0 {
+		existing.Patterns = overlay.GetPatterns()
+	}
+	if len(overlay.GetPatches()) > 0 {
+		existing.Patches = overlay.GetPatches()
+	}
+	if len(overlay.GetTransforms()) > 0 {
+		existing.Transforms = overlay.GetTransforms()
+	}
+	if overlay.GetShade() != nil {
+		existing.Shade = overlay.GetShade()
+	}
+}
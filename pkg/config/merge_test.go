@@ -0,0 +1,135 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/config"
+)
+
+func goModImport(pkg string, patterns ...string) *anyvendor.Import {
+	return &anyvendor.Import{ImportType: &anyvendor.Import_GoMod{GoMod: &anyvendor.GoModImport{
+		Package:  pkg,
+		Patterns: patterns,
+	}}}
+}
+
+var _ = Describe("Merge", func() {
+	It("overlays imports by package, and appends everything else", func() {
+		base := &anyvendor.Config{
+			Local:   &anyvendor.Local{Patterns: []string{"api/**/*.proto"}},
+			Imports: []*anyvendor.Import{goModImport("github.com/foo/a", "a/*.proto"), goModImport("github.com/foo/b", "b/*.proto")},
+			Patch:   &anyvendor.Patch{ImportRewrites: []*anyvendor.ImportRewrite{{From: "a/", To: "b/"}}},
+		}
+		overlay := &anyvendor.Config{
+			Local:          &anyvendor.Local{Patterns: []string{"protos/*.proto"}},
+			Imports:        []*anyvendor.Import{goModImport("github.com/foo/b", "b/v2/*.proto"), goModImport("github.com/foo/c", "c/*.proto")},
+			ResolveImports: true,
+		}
+		overlay.Imports[0].GetGoMod().Patches = []string{"b.patch"}
+
+		merged := config.Merge(base, overlay)
+		Expect(merged.GetLocal().GetPatterns()).To(Equal([]string{"api/**/*.proto", "protos/*.proto"}))
+		Expect(merged.GetImports()).To(HaveLen(3))
+		Expect(merged.GetImports()[0].GetGoMod().GetPatterns()).To(Equal([]string{"a/*.proto"}))
+		Expect(merged.GetImports()[1].GetGoMod().GetPatterns()).To(Equal([]string{"b/v2/*.proto"}))
+		Expect(merged.GetImports()[1].GetGoMod().GetPatches()).To(Equal([]string{"b.patch"}))
+		Expect(merged.GetImports()[2].GetGoMod().GetPackage()).To(Equal("github.com/foo/c"))
+		Expect(merged.GetResolveImports()).To(BeTrue())
+		Expect(merged.GetPatch().GetImportRewrites()).To(HaveLen(1))
+
+		// neither config is modified
+		Expect(base.GetImports()[1].GetGoMod().GetPatterns()).To(Equal([]string{"b/*.proto"}))
+		Expect(base.GetLocal().GetPatterns()).To(HaveLen(1))
+		Expect(overlay.GetImports()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("includes", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(dir, "base"), 0755)).To(Succeed())
+	})
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	It("merges the included files beneath the config", func() {
+		write("base/common.yaml", `
+imports:
+- goMod:
+    package: github.com/foo/a
+    patterns: [a/*.proto]
+`)
+		write("base/team.textproto", `
+includes: "common.yaml"
+imports { go_mod { package: "github.com/foo/b" patterns: "b/*.proto" } }
+resolve_imports: true
+`)
+		cfg, err := config.LoadConfig(write("anyvendor.yaml", `
+includes:
+- base/team.textproto
+imports:
+- goMod:
+    package: github.com/foo/a
+    patterns: [a/v2/*.proto]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetIncludes()).To(BeEmpty())
+		Expect(cfg.GetResolveImports()).To(BeTrue())
+		Expect(cfg.GetImports()).To(HaveLen(2))
+		Expect(cfg.GetImports()[0].GetGoMod().GetPatterns()).To(Equal([]string{"a/v2/*.proto"}))
+		Expect(cfg.GetImports()[1].GetGoMod().GetPackage()).To(Equal("github.com/foo/b"))
+	})
+	It("validates the merged config, rather than the included files", func() {
+		// patterns are required, and only set by the including file
+		write("base/common.yaml", "imports:\n- goMod:\n    package: github.com/foo/a\n")
+		_, err := config.LoadConfig(write("anyvendor.yaml", "includes: [base/common.yaml]\nimports:\n- goMod:\n    package: github.com/foo/a\n    patterns: [a/*.proto]\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = config.LoadConfig(write("invalid.yaml", "includes: [base/common.yaml]\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(filepath.Join(dir, "invalid.yaml") + ": "))
+	})
+	It("merges a file included by several others once", func() {
+		write("base/common.yaml", "local:\n  patterns: [api/*.proto]\n")
+		write("base/a.yaml", "includes: [common.yaml]\n")
+		write("base/b.yaml", "includes: [./common.yaml]\n")
+		cfg, err := config.LoadConfig(write("anyvendor.yaml", "includes: [base/a.yaml, base/b.yaml]\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GetLocal().GetPatterns()).To(Equal([]string{"api/*.proto"}))
+	})
+	It("reports the position of validation errors in the including file", func() {
+		write("base/common.yaml", "imports:\n- goMod:\n    package: github.com/foo/a\n    patterns: [a/*.proto]\n")
+		_, err := config.LoadConfig(write("anyvendor.yaml",
+			"includes: [base/common.yaml]\nimports:\n- goMod:\n    package: github.com/foo/b\n    patterns: []\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(filepath.Join(dir, "anyvendor.yaml") + ":5:15: "))
+		Expect(err.Error()).To(ContainSubstring("Imports[0]"))
+	})
+	It("rejects include cycles", func() {
+		write("base/a.yaml", "includes: [../anyvendor.yaml]\n")
+		_, err := config.LoadConfig(write("anyvendor.yaml", "includes: [base/a.yaml]\n"))
+		Expect(eris.Is(err, config.IncludeCycleError)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("anyvendor.yaml -> " + filepath.Join(dir, "base", "a.yaml") + " -> "))
+	})
+	It("reports errors in included files", func() {
+		write("base/common.yaml", "imports:\n- goMod:\n    pattern: [a/*.proto]\n")
+		_, err := config.LoadConfig(write("anyvendor.yaml", "includes: [base/common.yaml]\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(filepath.Join(dir, "base", "common.yaml") + ":3:5: "))
+
+		_, err = config.LoadConfig(write("missing.yaml", "includes: [base/missing.yaml]\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(filepath.Join(dir, "missing.yaml") + ": "))
+	})
+})
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+var yamlLineRegex = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// returns the root node of the document, or nil if it is empty
+func parseYAML(filename string, content []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		configErr := &Error{File: filename, Err: err}
+		if match := yamlLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			configErr.Line, _ = strconv.Atoi(match[1])
+			configErr.Err = eris.New(strings.TrimPrefix(err.Error(), match[0]))
+		}
+		return nil, configErr
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+/*
+unmarshal the YAML node into the message. The node is checked against the fields of the message first, so unknown
+fields and values of the wrong type are reported at their position, and is then converted to JSON for protojson.
+*/
+func unmarshalYAML(filename string, node *yaml.Node, msg proto.Message) error {
+	if node == nil {
+		return nil
+	}
+	d := yamlDecoder{filename: filename}
+	value, err := d.message(node, msg.ProtoReflect().Descriptor())
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := protojson.Unmarshal(content, msg); err != nil {
+		return &Error{File: filename, Err: err}
+	}
+	return nil
+}
+
+type yamlDecoder struct {
+	filename string
+}
+
+func (d yamlDecoder) errorAt(node *yaml.Node, format string, args ...interface{}) error {
+	return &Error{File: d.filename, Line: node.Line, Column: node.Column, Err: eris.Errorf(format, args...)}
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// returns the field of the message with the key as its json or proto name
+func fieldByKey(md protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByJSONName(key); fd != nil {
+		return fd
+	}
+	return md.Fields().ByName(protoreflect.Name(key))
+}
+
+func (d yamlDecoder) message(node *yaml.Node, md protoreflect.MessageDescriptor) (map[string]interface{}, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, d.errorAt(node, "expected an object for %s", md.Name())
+	}
+	result := map[string]interface{}{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fd := fieldByKey(md, key.Value)
+		if fd == nil {
+			return nil, d.errorAt(key, "unknown field %q in %s", key.Value, md.Name())
+		}
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if isNull(value) {
+			continue
+		}
+		converted, err := d.field(value, fd)
+		if err != nil {
+			return nil, err
+		}
+		result[fd.JSONName()] = converted
+	}
+	return result, nil
+}
+
+func (d yamlDecoder) field(node *yaml.Node, fd protoreflect.FieldDescriptor) (interface{}, error) {
+	switch {
+	case fd.IsList():
+		if node.Kind != yaml.SequenceNode {
+			return nil, d.errorAt(node, "expected a list for %s", fd.Name())
+		}
+		list := []interface{}{}
+		for _, item := range node.Content {
+			converted, err := d.singular(item, fd)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	case fd.IsMap():
+		if node.Kind != yaml.MappingNode {
+			return nil, d.errorAt(node, "expected an object for %s", fd.Name())
+		}
+		entries := map[string]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			converted, err := d.singular(node.Content[i+1], fd.MapValue())
+			if err != nil {
+				return nil, err
+			}
+			entries[node.Content[i].Value] = converted
+		}
+		return entries, nil
+	}
+	return d.singular(node, fd)
+}
+
+func (d yamlDecoder) singular(node *yaml.Node, fd protoreflect.FieldDescriptor) (interface{}, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		return d.message(node, fd.Message())
+	}
+	if node.Kind != yaml.ScalarNode {
+		return nil, d.errorAt(node, "expected a %s for %s", fd.Kind(), fd.Name())
+	}
+	/*
+		every scalar may reference environment variables, so values of any type can come from them. Errors quote the
+		value as written, as the interpolated one may hold a secret
+	*/
+	text, err := interpolate(node.Value)
+	if err != nil {
+		return nil, &Error{File: d.filename, Line: node.Line, Column: node.Column, Err: err}
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return text, nil
+	case protoreflect.BoolKind:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, d.errorAt(node, "expected a bool for %s, got %q", fd.Name(), node.Value)
+		}
+		return value, nil
+	case protoreflect.EnumKind:
+		if _, err := strconv.Atoi(text); err == nil {
+			return json.Number(text), nil
+		}
+		if fd.Enum().Values().ByName(protoreflect.Name(text)) == nil {
+			return nil, d.errorAt(node, "unknown value %q for %s", node.Value, fd.Enum().Name())
+		}
+		return text, nil
+	}
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return nil, d.errorAt(node, "expected a number for %s, got %q", fd.Name(), node.Value)
+	}
+	return json.Number(text), nil
+}
+
+// an error returned by the generated validation
+type fieldError interface {
+	Field() string
+	Cause() error
+}
+
+var fieldIndexRegex = regexp.MustCompile(`^(\w+)\[(\d+)\]$`)
+
+// returns the fields of the validation error, and of its causes, without their indexes, followed by its reason
+func validationSignature(err error) string {
+	var parts []string
+	for err != nil {
+		fe, ok := err.(fieldError)
+		if !ok {
+			parts = append(parts, err.Error())
+			break
+		}
+		name := fe.Field()
+		if match := fieldIndexRegex.FindStringSubmatch(name); match != nil {
+			name = match[1]
+		}
+		parts = append(parts, name)
+		if reason, ok := err.(interface{ Reason() string }); ok {
+			parts = append(parts, reason.Reason())
+		}
+		err = fe.Cause()
+	}
+	return strings.Join(parts, ".")
+}
+
+/*
+returns the position of the node the validation error refers to, by following the fields of the error, and of its
+causes, as deep into the document as they exist
+*/
+func validationPosition(root *yaml.Node, md protoreflect.MessageDescriptor, err error) (int, int) {
+	if root == nil {
+		return 0, 0
+	}
+	node := root
+	for err != nil {
+		fe, ok := err.(fieldError)
+		if !ok {
+			break
+		}
+		name, index := fe.Field(), -1
+		if match := fieldIndexRegex.FindStringSubmatch(name); match != nil {
+			name = match[1]
+			index, _ = strconv.Atoi(match[2])
+		}
+		child, fd := childField(node, md, name)
+		if child == nil {
+			break
+		}
+		node = child
+		if index >= 0 {
+			if node.Kind != yaml.SequenceNode || index >= len(node.Content) {
+				break
+			}
+			node = node.Content[index]
+		}
+		if fd.Message() == nil {
+			break
+		}
+		md = fd.Message()
+		err = fe.Cause()
+	}
+	return node.Line, node.Column
+}
+
+// find the value of the field with the go name in the mapping node
+func childField(node *yaml.Node, md protoreflect.MessageDescriptor, goName string) (*yaml.Node, protoreflect.FieldDescriptor) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fd := fieldByKey(md, node.Content[i].Value)
+		if fd != nil && strings.EqualFold(strings.ReplaceAll(string(fd.Name()), "_", ""), goName) {
+			value := node.Content[i+1]
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			return value, fd
+		}
+	}
+	return nil, nil
+}
@@ -1,7 +1,6 @@
 package git
 
 import (
-	"log"
 	"os"
 	"strings"
 
@@ -9,6 +8,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/solo-io/anyvendor/pkg/redact"
 )
 
 // set to override cache dir
@@ -40,14 +40,16 @@ func (c *GitVendorCache) EnsureCheckedOut(
 	}
 	var authMethod transport.AuthMethod
 	if authToken != "" {
+		// the token may be part of the url, or of the errors of the remote
+		redact.Register(authToken)
 		authMethod = &http.BasicAuth{Username: authUser, Password: authToken}
 	}
 	var repo *git.Repository
 	if repoExists {
-		log.Printf("using repo %v in local cache %v", url, repoDir)
+		redact.Logf("using repo %v in local cache %v", url, repoDir)
 		repo, err = git.PlainOpen(repoDir)
 	} else {
-		log.Printf("cloning repo %v to local cache %v", url, repoDir)
+		redact.Logf("cloning repo %v to local cache %v", url, repoDir)
 		repo, err = git.PlainClone(repoDir, false, &git.CloneOptions{
 			URL:      url,
 			Progress: ProgressOut,
@@ -58,7 +60,7 @@ func (c *GitVendorCache) EnsureCheckedOut(
 		return err
 	}
 
-	log.Printf("fetching repo")
+	redact.Logf("fetching repo")
 	if err := repo.Fetch(&git.FetchOptions{
 		RemoteName:      "origin",
 		RefSpecs:        nil,
@@ -80,7 +82,7 @@ func (c *GitVendorCache) EnsureCheckedOut(
 
 	// allow disabling hard reset
 	if os.Getenv("DISABLE_HARD_RESET") != "1" {
-		log.Printf("performing hard reset")
+		redact.Logf("performing hard reset")
 		if err := wt.Reset(&git.ResetOptions{
 			Mode: git.HardReset,
 		}); err != nil {
@@ -96,7 +98,7 @@ func (c *GitVendorCache) EnsureCheckedOut(
 	var ref plumbing.ReferenceName
 	switch {
 	case sha != "":
-		log.Printf("checking out with sha %v", sha)
+		redact.Logf("checking out with sha %v", sha)
 		checkout.Hash = plumbing.NewHash(sha)
 		ref = plumbing.NewBranchReferenceName(sha)
 		if err := wt.Checkout(checkout); err != nil {
@@ -104,14 +106,14 @@ func (c *GitVendorCache) EnsureCheckedOut(
 		}
 
 	case tag != "":
-		log.Printf("checking out with tag %v", tag)
+		redact.Logf("checking out with tag %v", tag)
 		checkout.Branch = plumbing.NewTagReferenceName(tag)
 		ref = plumbing.NewTagReferenceName(tag)
 		if err := wt.Checkout(checkout); err != nil {
 			return err
 		}
 
-		log.Printf("pulling ref %v", ref)
+		redact.Logf("pulling ref %v", ref)
 		if err := wt.Pull(&git.PullOptions{
 			RemoteName:    "origin",
 			ReferenceName: ref,
@@ -129,6 +131,20 @@ func (c *GitVendorCache) EnsureCheckedOut(
 	return nil
 }
 
+// GetCommit returns the commit checked out in the cached repository.
+func (c *GitVendorCache) GetCommit(url string) (string, error) {
+	repoDir, _ := c.GetRepoDir(url)
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
 func (c *GitVendorCache) GetRepoDir(url string) (string, string) {
 	repoDir := strings.TrimPrefix(url, "git://")
 	repoDir = strings.TrimPrefix(repoDir, "https://")
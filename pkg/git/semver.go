@@ -0,0 +1,237 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"golang.org/x/mod/semver"
+)
+
+var InvalidConstraintError = eris.New("invalid semver constraint")
+
+/*
+Constraint is a semver constraint on the tags of a git repository. Alternatives are separated by ||, and each
+alternative is a list of space or comma separated terms which must all match, e.g.
+
+	^1.4           >=1.4.0 <2.0.0
+	~2.1.0         >=2.1.0 <2.2.0
+	1.2.x          >=1.2.0 <1.3.0
+	>=1.2.0 <1.5   >=1.2.0 <1.5.0
+
+Tags may be prefixed with v. As with npm, a pre-release tag only matches an alternative which mentions a
+pre-release of the same major, minor and patch version, so >=1.4.0-rc.0 matches v1.4.0-rc.1 but not v1.5.0-rc.1.
+*/
+type Constraint struct {
+	raw          string
+	alternatives []alternative
+}
+
+// the bounds of an alternative, which must all match
+type alternative struct {
+	bounds []bound
+	// the versions, without their pre-release, whose pre-releases the alternative mentions, e.g. v1.4.0
+	prereleases map[string]bool
+}
+
+// a comparison against a canonical semver version, such as v1.2.3
+type bound struct {
+	op      string
+	version string
+}
+
+func (b bound) check(version string) bool {
+	cmp := semver.Compare(version, b.version)
+	switch b.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// ParseConstraint parses a constraint, such as ^1.4 or ~2.1.0.
+func ParseConstraint(constraint string) (*Constraint, error) {
+	c := &Constraint{raw: constraint}
+	for _, alt := range strings.Split(constraint, "||") {
+		terms := strings.FieldsFunc(alt, func(r rune) bool {
+			return r == ' ' || r == ',' || r == '\t'
+		})
+		if len(terms) == 0 {
+			return nil, eris.Wrapf(InvalidConstraintError, "%q has an empty alternative", constraint)
+		}
+		parsed := alternative{prereleases: map[string]bool{}}
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// an operator separated from its version, e.g. >= 1.2.0
+			if strings.Trim(term, "^~=<>") == "" && i+1 < len(terms) {
+				i++
+				term += terms[i]
+			}
+			termBounds, prerelease, err := parseTerm(term)
+			if err != nil {
+				return nil, eris.Wrapf(InvalidConstraintError, "%q: %s", constraint, err.Error())
+			}
+			parsed.bounds = append(parsed.bounds, termBounds...)
+			if prerelease != "" {
+				parsed.prereleases[prerelease] = true
+			}
+		}
+		c.alternatives = append(c.alternatives, parsed)
+	}
+	return c, nil
+}
+
+/*
+expand a single term, such as ^1.4, into the bounds it is equivalent to, along with the version, without its
+pre-release, whose pre-releases it mentions, if any
+*/
+func parseTerm(term string) ([]bound, string, error) {
+	op := term[:len(term)-len(strings.TrimLeft(term, "^~=<>"))]
+	parts, suffix, err := parseVersion(strings.TrimPrefix(term[len(op):], "v"))
+	if err != nil {
+		return nil, "", err
+	}
+	specified := len(parts)
+	for len(parts) < 3 {
+		parts = append(parts, 0)
+	}
+	lower := fmt.Sprintf("v%d.%d.%d%s", parts[0], parts[1], parts[2], suffix)
+	var prerelease string
+	if strings.HasPrefix(suffix, "-") {
+		prerelease = fmt.Sprintf("v%d.%d.%d", parts[0], parts[1], parts[2])
+	}
+	// the lowest version, pre-releases included, past every version matching the parts up to bumped
+	upper := func(bumped int) string {
+		next := []int{parts[0], parts[1], parts[2]}
+		next[bumped]++
+		for i := bumped + 1; i < 3; i++ {
+			next[i] = 0
+		}
+		return fmt.Sprintf("v%d.%d.%d-0", next[0], next[1], next[2])
+	}
+	if specified == 0 {
+		// matches any version
+		return []bound{{op: ">=", version: "v0.0.0-0"}}, prerelease, nil
+	}
+
+	switch op {
+	case "", "=":
+		if specified == 3 {
+			return []bound{{op: "=", version: lower}}, prerelease, nil
+		}
+		return []bound{{op: ">=", version: lower}, {op: "<", version: upper(specified - 1)}}, prerelease, nil
+	case "^":
+		var bumped int
+		switch {
+		case parts[0] > 0 || specified == 1:
+			bumped = 0
+		case parts[1] > 0 || specified == 2:
+			bumped = 1
+		default:
+			bumped = 2
+		}
+		return []bound{{op: ">=", version: lower}, {op: "<", version: upper(bumped)}}, prerelease, nil
+	case "~":
+		bumped := 1
+		if specified == 1 {
+			bumped = 0
+		}
+		return []bound{{op: ">=", version: lower}, {op: "<", version: upper(bumped)}}, prerelease, nil
+	case ">=", "<":
+		return []bound{{op: op, version: lower}}, prerelease, nil
+	case ">":
+		if specified == 3 {
+			return []bound{{op: ">", version: lower}}, prerelease, nil
+		}
+		return []bound{{op: ">=", version: upper(specified - 1)}}, prerelease, nil
+	case "<=":
+		if specified == 3 {
+			return []bound{{op: "<=", version: lower}}, prerelease, nil
+		}
+		return []bound{{op: "<", version: upper(specified - 1)}}, prerelease, nil
+	}
+	return nil, "", fmt.Errorf("unknown operator %q", op)
+}
+
+// returns the numeric parts of the version which are specified, stopping at the first wildcard, and its pre-release
+func parseVersion(version string) ([]int, string, error) {
+	var suffix string
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version, suffix = version[:i], version[i:]
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid version %q", version)
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) > 3 {
+		return nil, "", fmt.Errorf("invalid version %q", version)
+	}
+	return parts, suffix, nil
+}
+
+// Check returns true if the tag is a semver version matching the constraint.
+func (c *Constraint) Check(tag string) bool {
+	version := canonicalVersion(tag)
+	if version == "" {
+		return false
+	}
+	prerelease := semver.Prerelease(version)
+	for _, alt := range c.alternatives {
+		if prerelease != "" && !alt.prereleases[strings.TrimSuffix(version, prerelease)] {
+			continue
+		}
+		matched := true
+		for _, b := range alt.bounds {
+			if !b.check(version) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Latest returns the newest of the tags matching the constraint, or false if none of them do.
+func (c *Constraint) Latest(tags []string) (string, bool) {
+	var latest string
+	for _, tag := range tags {
+		if c.Check(tag) && (latest == "" || semver.Compare(canonicalVersion(tag), canonicalVersion(latest)) > 0) {
+			latest = tag
+		}
+	}
+	return latest, latest != ""
+}
+
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// returns the tag as a canonical semver version, with a v prefix, or an empty string if it is not one
+func canonicalVersion(tag string) string {
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	if !semver.IsValid(tag) {
+		return ""
+	}
+	return semver.Canonical(tag)
+}
@@ -0,0 +1,133 @@
+package git_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/pkg/git"
+	"github.com/solo-io/anyvendor/pkg/outdated"
+	"github.com/solo-io/anyvendor/pkg/redact"
+)
+
+var _ = Describe("Constraint", func() {
+	tags := []string{"v0.9.0", "v1.3.9", "v1.4.0", "v1.4.7", "v1.9.2", "v2.0.0-rc.1", "v2.0.0", "2.1.0", "v2.1.5", "v2.2.0", "latest"}
+
+	DescribeTable("resolves the newest matching tag",
+		func(constraint, expected string) {
+			c, err := git.ParseConstraint(constraint)
+			Expect(err).NotTo(HaveOccurred())
+			latest, ok := c.Latest(tags)
+			Expect(ok).To(Equal(expected != ""))
+			Expect(latest).To(Equal(expected))
+		},
+		Entry("caret", "^1.4", "v1.9.2"),
+		Entry("caret below 1.0.0", "^0.9", "v0.9.0"),
+		Entry("tilde", "~2.1.0", "v2.1.5"),
+		Entry("tilde with a major version", "~1", "v1.9.2"),
+		Entry("wildcard", "1.4.x", "v1.4.7"),
+		Entry("any", "*", "v2.2.0"),
+		Entry("exact", "2.1.0", "2.1.0"),
+		Entry("range", ">=1.4.0 <1.9", "v1.4.7"),
+		Entry("separated operator", ">= 1.4.0, < 2", "v1.9.2"),
+		Entry("alternatives", "~1.3.0 || ~2.1", "v2.1.5"),
+		Entry("greater than a partial version", ">1.4", "v2.2.0"),
+		Entry("pre-release", ">=2.0.0-rc.0 <2.0.0", "v2.0.0-rc.1"),
+		Entry("no match", "^3", ""),
+	)
+	It("only matches pre-releases of the versions an alternative mentions", func() {
+		c, err := git.ParseConstraint(">=1.4.0-rc.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Check("v1.4.0-rc.1")).To(BeTrue())
+		Expect(c.Check("v1.5.0-rc.1")).To(BeFalse())
+		Expect(c.Check("v1.5.0")).To(BeTrue())
+
+		c, err = git.ParseConstraint(">=2.0.0-rc.0 <2.0.0 || >=1.0.0 <3.0.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Check("v2.0.0-rc.1")).To(BeTrue())
+		Expect(c.Check("v2.1.0-rc.1")).To(BeFalse())
+		Expect(c.Check("v2.1.0")).To(BeTrue())
+	})
+	It("rejects invalid constraints", func() {
+		for _, constraint := range []string{"", "^1.a", "=>1.2.0", "1.2.3.4", "^1 ||"} {
+			_, err := git.ParseConstraint(constraint)
+			Expect(err).To(HaveOccurred(), constraint)
+			Expect(eris.Is(err, git.InvalidConstraintError)).To(BeTrue())
+		}
+	})
+})
+
+var _ = Describe("Update", func() {
+	var listRemoteTags = git.ListRemoteTags
+
+	BeforeEach(func() {
+		git.ListRemoteTags = func(url, authUser, authToken string) ([]string, error) {
+			return []string{"v1.4.0", "v1.5.1", "v2.0.0"}, nil
+		}
+	})
+	AfterEach(func() {
+		git.ListRemoteTags = listRemoteTags
+	})
+
+	It("pins repositories to the newest tag matching their constraint", func() {
+		opts := &git.VendorOptions{GitRepositories: []git.GitRepository{
+			{URL: "https://github.com/foo/a", Tag: "v1.4.0", Constraint: "^1.4"},
+			{URL: "https://github.com/foo/b", Constraint: "~1.5.0"},
+			{URL: "https://github.com/foo/c", Tag: "v1.5.1", Constraint: "^1"},
+			{URL: "https://github.com/foo/d", SHA: "6c073b08f7987018cbb2cb9a5747c84913b3608e"},
+		}}
+		updates, err := opts.Update()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(Equal([]git.TagUpdate{
+			{URL: "https://github.com/foo/a", Constraint: "^1.4", From: "v1.4.0", To: "v1.5.1"},
+			{URL: "https://github.com/foo/b", Constraint: "~1.5.0", To: "v1.5.1"},
+		}))
+		Expect(updates[1].String()).To(Equal("https://github.com/foo/b: (unpinned) -> v1.5.1 (~1.5.0)"))
+		Expect(opts.GitRepositories[0].Tag).To(Equal("v1.5.1"))
+		Expect(opts.GitRepositories[1].Tag).To(Equal("v1.5.1"))
+		Expect(opts.GitRepositories[3].Tag).To(BeEmpty())
+
+		// no repository is moved when any of them can not be resolved
+		opts.GitRepositories[0].Tag = "v1.4.0"
+		opts.GitRepositories = append(opts.GitRepositories, git.GitRepository{URL: "https://github.com/foo/e", Constraint: "^3"})
+		_, err = opts.Update()
+		Expect(eris.Is(err, git.NoMatchingTagError)).To(BeTrue())
+		Expect(opts.GitRepositories[0].Tag).To(Equal("v1.4.0"))
+	})
+})
+
+var _ = Describe("Outdated", func() {
+	var listRemoteTags = git.ListRemoteTags
+
+	BeforeEach(func() {
+		git.ListRemoteTags = func(url, authUser, authToken string) ([]string, error) {
+			return []string{"v1.4.0", "v1.5.1", "v2.0.0", "v2.1.0-rc.1"}, nil
+		}
+	})
+	AfterEach(func() {
+		git.ListRemoteTags = listRemoteTags
+	})
+
+	It("reports the current and newest tag of every repository", func() {
+		report, err := git.VendorOptions{GitRepositories: []git.GitRepository{
+			{URL: "https://github.com/foo/a", Tag: "v1.4.0", Constraint: "^1.4"},
+			{URL: "https://github.com/foo/b", Constraint: "~1.5.0"},
+			{URL: "https://github.com/foo/c", Tag: "v2.0.0"},
+			{URL: "https://github.com/foo/d", SHA: "6c073b08f7987018cbb2cb9a5747c84913b3608e"},
+		}}.Outdated()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(outdated.Report{
+			{Type: "git", Name: "https://github.com/foo/a", Current: "v1.4.0", Latest: "v2.0.0", LatestMatching: "v1.5.1", Outdated: true},
+			{Type: "git", Name: "https://github.com/foo/b", Current: "v1.5.1", Latest: "v2.0.0", LatestMatching: "v1.5.1", Outdated: true},
+			{Type: "git", Name: "https://github.com/foo/c", Current: "v2.0.0", Latest: "v2.0.0"},
+			{Type: "git", Name: "https://github.com/foo/d", Current: "6c073b08f7987018cbb2cb9a5747c84913b3608e", Latest: "v2.0.0"},
+		}))
+	})
+})
+
+var _ = Describe("ListRemoteTags", func() {
+	It("registers the auth token to be redacted", func() {
+		_, err := git.ListRemoteTags("http://127.0.0.1:1/foo/a", "user", "list-remote-tags-token")
+		Expect(err).To(HaveOccurred())
+		Expect(redact.String("token: list-remote-tags-token")).NotTo(ContainSubstring("list-remote-tags-token"))
+	})
+})
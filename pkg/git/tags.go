@@ -0,0 +1,168 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/pkg/outdated"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"golang.org/x/mod/semver"
+)
+
+var NoMatchingTagError = eris.New("no tag matches the constraint")
+
+// set to override how the tags of remote repositories are listed
+var ListRemoteTags = func(url, authUser, authToken string) ([]string, error) {
+	var authMethod transport.AuthMethod
+	if authToken != "" {
+		// the token may be part of the url, or of the errors of the remote
+		redact.Register(authToken)
+		authMethod = &http.BasicAuth{Username: authUser, Password: authToken}
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	refs, err := remote.List(&git.ListOptions{Auth: authMethod})
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+	return tags, nil
+}
+
+// returns the newest tag of the repository matching its constraint
+func (r *GitRepository) latestTag() (string, error) {
+	constraint, err := ParseConstraint(r.Constraint)
+	if err != nil {
+		return "", err
+	}
+	tags, err := ListRemoteTags(r.URL, r.AuthUser, r.AuthToken)
+	if err != nil {
+		return "", err
+	}
+	tag, ok := constraint.Latest(tags)
+	if !ok {
+		return "", eris.Wrapf(NoMatchingTagError, "%s: %s", r.URL, r.Constraint)
+	}
+	return tag, nil
+}
+
+// returns a copy of the repository pinned to the newest tag matching its constraint, unless it is already pinned
+func (r GitRepository) resolved() (GitRepository, error) {
+	if r.Constraint == "" || r.SHA != "" || r.Tag != "" {
+		return r, nil
+	}
+	tag, err := r.latestTag()
+	if err != nil {
+		return r, err
+	}
+	r.Tag = tag
+	return r, nil
+}
+
+// TagUpdate is a repository whose tag was moved by Update.
+type TagUpdate struct {
+	URL        string
+	Constraint string
+	// empty when the repository was not pinned to a tag
+	From string
+	To   string
+}
+
+func (u TagUpdate) String() string {
+	from := u.From
+	if from == "" {
+		from = "(unpinned)"
+	}
+	return fmt.Sprintf("%s: %s -> %s (%s)", u.URL, from, u.To, u.Constraint)
+}
+
+/*
+Update pins every repository with a constraint to the newest remote tag matching it, clearing its SHA, and returns
+the repositories whose tag moved. The options are only updated in memory, and are left untouched when the tags
+of any repository can not be resolved. Callers save the returned tags wherever they define their repositories, as
+an unpinned repository is resolved again every time it is vendored.
+*/
+func (r *VendorOptions) Update() ([]TagUpdate, error) {
+	// resolve every tag before moving any, so a failure leaves the options as they were
+	tags := map[int]string{}
+	for i, repository := range r.GitRepositories {
+		if repository.Constraint == "" {
+			continue
+		}
+		tag, err := repository.latestTag()
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = tag
+	}
+	var updates []TagUpdate
+	for i := range r.GitRepositories {
+		repository := &r.GitRepositories[i]
+		tag, ok := tags[i]
+		if !ok || (tag == repository.Tag && repository.SHA == "") {
+			continue
+		}
+		updates = append(updates, TagUpdate{
+			URL:        repository.URL,
+			Constraint: repository.Constraint,
+			From:       repository.Tag,
+			To:         tag,
+		})
+		repository.Tag, repository.SHA = tag, ""
+	}
+	return updates, nil
+}
+
+/*
+Outdated reports the version every repository is vendored at, along with the newest semver tag of the repository
+and, for repositories with a constraint, the newest tag matching it. Repositories pinned to a SHA are never
+reported as outdated, as the SHA cannot be compared with the tags.
+*/
+func (r VendorOptions) Outdated() (outdated.Report, error) {
+	anyVersion, err := ParseConstraint("*")
+	if err != nil {
+		return nil, err
+	}
+	var report outdated.Report
+	for _, repository := range r.GitRepositories {
+		tags, err := ListRemoteTags(repository.URL, repository.AuthUser, repository.AuthToken)
+		if err != nil {
+			return nil, err
+		}
+		latest, _ := anyVersion.Latest(tags)
+		source := outdated.Source{
+			Type:    provenance.SourceTypeGit,
+			Name:    repository.URL,
+			Current: repository.version(),
+			Latest:  latest,
+		}
+		if repository.Constraint != "" {
+			constraint, err := ParseConstraint(repository.Constraint)
+			if err != nil {
+				return nil, err
+			}
+			source.LatestMatching, _ = constraint.Latest(tags)
+			if source.Current == "" {
+				// an unpinned repository is vendored at the newest matching tag
+				source.Current = source.LatestMatching
+			}
+		}
+		current := canonicalVersion(source.Current)
+		source.Outdated = current != "" && latest != "" && semver.Compare(current, canonicalVersion(latest)) < 0
+		report = append(report, source)
+	}
+	return report, nil
+}
@@ -2,34 +2,166 @@ package git
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/license"
 	"github.com/solo-io/anyvendor/pkg/manager"
+	"github.com/solo-io/anyvendor/pkg/policy"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"github.com/solo-io/anyvendor/pkg/transform"
 	"github.com/spf13/afero"
 )
 
 // vendor files from git repositories
 type VendorOptions struct {
 	GitRepositories []GitRepository
+
+	// follow the imports of every vendored proto file, and vendor the imported files from
+	// any of the repositories until the closure is complete
+	ResolveImports bool
+	// when resolving imports, skip the well known imports (google/protobuf/*), which are assumed to be provided by
+	// protoc, and log them
+	SkipWellKnownImports bool
+	// optional policy every repository must comply with, nothing is vendored if any repository does not
+	Policy *anyvendor.Policy
+	// prepend a comment header to every vendored file recording the repository, tag or commit, and path it was
+	// vendored from, see manager.StampProvenance
+	ProvenanceHeaders bool
+	// do not write a .anyvendor.json file, describing the repository and the files vendored from it, to the
+	// vendored root of every repository
+	SkipMetadata bool
 }
 
 func (r VendorOptions) Vendor(cache *GitVendorCache, vendorDir string) error {
+	// pin the repositories with a constraint, without changing the caller's options
+	repositories := make([]GitRepository, 0, len(r.GitRepositories))
 	for _, repository := range r.GitRepositories {
-		if err := repository.Vendor(cache, vendorDir); err != nil {
+		resolved, err := repository.resolved()
+		if err != nil {
+			return err
+		}
+		repositories = append(repositories, resolved)
+	}
+	r.GitRepositories = repositories
+	if err := r.vendor(cache, vendorDir); err != nil {
+		return err
+	}
+	return r.writeNotices(cache, vendorDir)
+}
+
+func (r VendorOptions) vendor(cache *GitVendorCache, vendorDir string) error {
+	var (
+		checkouts []*repositoryCheckout
+		files     []string
+		sources   []protoutils.ImportSource
+	)
+	for i := range r.GitRepositories {
+		checkout, err := r.GitRepositories[i].checkout(cache)
+		if err != nil {
 			return err
 		}
+		checkouts = append(checkouts, checkout)
+		files = append(files, checkout.filesToCopy...)
+		sources = append(sources, protoutils.ImportSource{
+			Dir:    checkout.cachedRepoDir,
+			Prefix: checkout.repoRelativePath,
+		})
+	}
+	if r.ResolveImports {
+		closure, err := protoutils.ImportClosure(files, sources, protoutils.ClosureOptions{
+			SkipWellKnownImports: r.SkipWellKnownImports,
+		})
+		if err != nil {
+			return err
+		}
+		if len(closure.SkippedWellKnownImports) > 0 {
+			redact.Logf("skipped well known imports, which must be provided by protoc: %s",
+				strings.Join(closure.SkippedWellKnownImports, ", "))
+		}
+		for _, file := range closure.Imported {
+			checkout := checkouts[file.Source]
+			checkout.filesToCopy = append(checkout.filesToCopy, file.Path)
+		}
+	}
+	if err := r.evaluatePolicy(checkouts); err != nil {
+		return err
+	}
+	for i, checkout := range checkouts {
+		if r.ProvenanceHeaders {
+			checkout.provenance = r.GitRepositories[i].provenance()
+		}
+		if err := checkout.copy(vendorDir); err != nil {
+			return err
+		}
+		if !r.SkipMetadata {
+			if err := r.GitRepositories[i].writeMetadata(cache, checkout, vendorDir); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+func (r VendorOptions) evaluatePolicy(checkouts []*repositoryCheckout) error {
+	if r.Policy == nil {
+		return nil
+	}
+	var sources []policy.Source
+	for i, checkout := range checkouts {
+		sources = append(sources, policy.Source{
+			Name:  r.GitRepositories[i].URL,
+			Dir:   checkout.cachedRepoDir,
+			Files: checkout.filesToCopy,
+		})
+	}
+	return policy.Evaluate(r.Policy, sources)
+}
+
+// write the aggregated notices of every repository which vendors its license files to the vendor dir
+func (r VendorOptions) writeNotices(cache *GitVendorCache, vendorDir string) error {
+	var sources []license.Source
+	for _, repository := range r.GitRepositories {
+		if repository.SkipLicenses {
+			continue
+		}
+		cachedRepoDir, _ := cache.GetRepoDir(repository.URL)
+		source, err := license.ReadSource(repository.URL, repository.version(), cachedRepoDir)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(vendorDir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(vendorDir, license.NoticesFile))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return license.WriteNotices(f, sources)
+}
+
 // vendor files from a git repository
 type GitRepository struct {
 	// The repo URL
 	URL string
-	// provide one of SHA or Tag
+	// provide one of SHA, Tag or Constraint
 	SHA string
 	Tag string
+	// a semver constraint, such as ^1.4 or ~2.1.0, the repository is vendored at the newest remote tag matching
+	// unless it is pinned to a SHA or Tag, see VendorOptions.Update
+	Constraint string
 
 	// HTTP Auth User (for private repository)
 	AuthUser string
@@ -40,9 +172,136 @@ type GitRepository struct {
 	MatchPatterns []string
 	// skip these dirs when vendoring files
 	SkipDirs []string
+	// optional transformer run on every file as it is vendored, with its path relative to the repository
+	Transformer transform.Transformer
+	// do not vendor the top level LICENSE, NOTICE and COPYING files of the repository, or list it in the
+	// THIRD_PARTY_NOTICES written by VendorOptions
+	SkipLicenses bool
+}
+
+// the commit, or tag, the repository is vendored at
+func (r *GitRepository) version() string {
+	if r.SHA != "" {
+		return r.SHA
+	}
+	return r.Tag
+}
+
+// the header stamped into the files vendored from the repository
+func (r *GitRepository) provenance() *provenance.Header {
+	return &provenance.Header{
+		Source:    r.URL,
+		Version:   r.version(),
+		Anyvendor: provenance.Version(),
+	}
+}
+
+// write the metadata file describing the repository, and the files copied from it, to its vendored root
+func (r *GitRepository) writeMetadata(cache *GitVendorCache, checkout *repositoryCheckout, vendorDir string) error {
+	commit, err := cache.GetCommit(r.URL)
+	if err != nil {
+		return err
+	}
+	fs := afero.NewOsFs()
+	metadata := provenance.Metadata{
+		Type:      provenance.SourceTypeGit,
+		Source:    r.URL,
+		Version:   r.version(),
+		Commit:    commit,
+		Patterns:  r.MatchPatterns,
+		Anyvendor: provenance.Version(),
+	}
+	for _, file := range checkout.copied {
+		hash, err := provenance.HashFile(fs, file.destination)
+		if err != nil {
+			return err
+		}
+		vendored, err := filepath.Rel(vendorDir, file.destination)
+		if err != nil {
+			return err
+		}
+		metadata.Files = append(metadata.Files, provenance.File{
+			Path:     file.path,
+			Vendored: filepath.ToSlash(vendored),
+			SHA256:   hash,
+		})
+	}
+	return provenance.WriteMetadata(fs, filepath.Join(vendorDir, checkout.repoRelativePath), metadata)
 }
 
 func (r *GitRepository) Vendor(cache *GitVendorCache, vendorDir string) error {
+	repository, err := r.resolved()
+	if err != nil {
+		return err
+	}
+	checkout, err := repository.checkout(cache)
+	if err != nil {
+		return err
+	}
+	return checkout.copy(vendorDir)
+}
+
+/*
+Diff writes a unified diff of the files vendored from the repository, against the files which would be vendored
+from the candidate sha or tag of it, to w. The files listed in the .anyvendor.json metadata of the repository which
+are no longer matched are shown as deleted. Files are named relative to the vendored root of the repository.
+*/
+func (r *GitRepository) Diff(cache *GitVendorCache, vendorDir, sha, tag string, w io.Writer) error {
+	candidate := *r
+	candidate.SHA, candidate.Tag = sha, tag
+	checkout, err := candidate.checkout(cache)
+	if err != nil {
+		return err
+	}
+	fs := afero.NewOsFs()
+	root := filepath.Join(vendorDir, checkout.repoRelativePath)
+	candidateFiles := map[string][]byte{}
+	for _, cachedFile := range checkout.filesToCopy {
+		relativePath := filepath.ToSlash(strings.TrimPrefix(cachedFile[len(checkout.cachedRepoDir):], string(filepath.Separator)))
+		content, err := afero.ReadFile(fs, cachedFile)
+		if err != nil {
+			return err
+		}
+		if checkout.transformer != nil && !checkout.licenses[cachedFile] {
+			if relativePath, content, err = checkout.transformer.Transform(relativePath, content); err != nil {
+				return err
+			}
+		}
+		candidateFiles[filepath.Join(root, filepath.FromSlash(relativePath))] = content
+	}
+	var vendored []string
+	metadata, err := provenance.ReadMetadata(fs, root)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, file := range metadata.Files {
+		vendored = append(vendored, filepath.Join(vendorDir, filepath.FromSlash(file.Vendored)))
+	}
+	return manager.DiffVendored(fs, root, vendored, candidateFiles, w)
+}
+
+// a repository which has been checked out in the cache, along with the files to vendor from it
+type repositoryCheckout struct {
+	fileCopier       manager.FileCopier
+	transformer      transform.Transformer
+	cachedRepoDir    string
+	repoRelativePath string
+	filesToCopy      []string
+	// the license files of the repository, which are copied verbatim
+	licenses map[string]bool
+	// stamped into every copied file other than the licenses when set, with the path of the file
+	provenance *provenance.Header
+	// the files which have been copied
+	copied []copiedFile
+}
+
+type copiedFile struct {
+	// relative to the root of the repository
+	path        string
+	destination string
+}
+
+func (r *GitRepository) checkout(cache *GitVendorCache) (*repositoryCheckout, error) {
 	if err := cache.EnsureCheckedOut(
 		r.URL,
 		r.SHA,
@@ -50,22 +309,75 @@ func (r *GitRepository) Vendor(cache *GitVendorCache, vendorDir string) error {
 		r.AuthUser,
 		r.AuthToken,
 	); err != nil {
-		return err
+		return nil, err
 	}
 	cachedRepoDir, repoRelativePath := cache.GetRepoDir(r.URL)
 
 	fileCopier := manager.NewCopier(afero.NewOsFs(), r.SkipDirs)
 	filesToCopy, err := fileCopier.GetMatches(r.MatchPatterns, cachedRepoDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	for _, cachedFile := range filesToCopy {
-		copiedFileSuffix := filepath.Join(repoRelativePath, cachedFile[len(cachedRepoDir):])
-		copiedFile := filepath.Join(vendorDir, copiedFileSuffix)
-		if _, err := fileCopier.Copy(cachedFile, copiedFile); err != nil {
-			return eris.Wrap(err, fmt.Sprintf("Error! %s - unable to copy file %s\n",
-				err.Error(), cachedFile))
+	licenses := map[string]bool{}
+	if !r.SkipLicenses {
+		licenseFiles, err := license.Find(cachedRepoDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, licenseFile := range licenseFiles {
+			if !containsString(filesToCopy, licenseFile) {
+				filesToCopy = append(filesToCopy, licenseFile)
+			}
+			licenses[licenseFile] = true
+		}
+	}
+	return &repositoryCheckout{
+		fileCopier:       fileCopier,
+		transformer:      r.Transformer,
+		cachedRepoDir:    cachedRepoDir,
+		repoRelativePath: repoRelativePath,
+		filesToCopy:      filesToCopy,
+		licenses:         licenses,
+	}, nil
+}
+
+func (c *repositoryCheckout) copy(vendorDir string) error {
+	fs := afero.NewOsFs()
+	for _, cachedFile := range c.filesToCopy {
+		relativePath := strings.TrimPrefix(cachedFile[len(c.cachedRepoDir):], string(filepath.Separator))
+		var destination string
+		if c.transformer != nil && !c.licenses[cachedFile] {
+			root := filepath.Join(vendorDir, c.repoRelativePath)
+			transformedFile, err := manager.TransformFile(fs, cachedFile, root, relativePath, c.transformer)
+			if err != nil {
+				return err
+			}
+			destination = transformedFile
+		} else {
+			destinationSuffix := filepath.Join(c.repoRelativePath, cachedFile[len(c.cachedRepoDir):])
+			destination = filepath.Join(vendorDir, destinationSuffix)
+			if _, err := c.fileCopier.Copy(cachedFile, destination); err != nil {
+				return eris.Wrap(err, fmt.Sprintf("Error! %s - unable to copy file %s\n",
+					err.Error(), cachedFile))
+			}
+		}
+		c.copied = append(c.copied, copiedFile{path: filepath.ToSlash(relativePath), destination: destination})
+		if c.provenance != nil && !c.licenses[cachedFile] {
+			header := *c.provenance
+			header.Path = filepath.ToSlash(relativePath)
+			if err := manager.StampProvenance(fs, destination, header); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
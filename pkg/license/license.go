@@ -0,0 +1,157 @@
+package license
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// the name of the aggregated notices file written to the vendor dir
+const NoticesFile = "THIRD_PARTY_NOTICES"
+
+// prefixes of the names of the files which hold the license, or notices, of a source
+var filePrefixes = []string{"LICENSE", "LICENCE", "NOTICE", "COPYING"}
+
+// Find returns the license, notice and copying files at the top level of dir, e.g. LICENSE, LICENSE.md or NOTICE.
+func Find(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || !IsLicenseFile(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	return files, nil
+}
+
+// IsLicenseFile returns true if the name of the file is that of a license, notice or copying file.
+func IsLicenseFile(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, prefix := range filePrefixes {
+		if upper == prefix || strings.HasPrefix(upper, prefix+".") || strings.HasPrefix(upper, prefix+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+var spdxIdentifier = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+(?:\s+(?:OR|AND|WITH)\s+[A-Za-z0-9.+-]+)*)`)
+
+// phrases which identify the text of a license, checked in order
+var licenseTexts = []struct {
+	spdx    string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"EPL-2.0", []string{"eclipse public license", "v 2.0"}},
+	{"EPL-1.0", []string{"eclipse public license", "v 1.0"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+}
+
+/*
+Detect returns the SPDX identifier of the license in the text, or an empty string if it is not recognized.
+An SPDX-License-Identifier line takes precedence, otherwise the text is matched against common licenses.
+*/
+func Detect(content []byte) string {
+	if match := spdxIdentifier.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	text := normalize(content)
+	for _, license := range licenseTexts {
+		matched := true
+		for _, phrase := range license.phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return license.spdx
+		}
+	}
+	return ""
+}
+
+// lower case the text, and collapse whitespace so phrases wrapped across lines still match
+func normalize(content []byte) string {
+	var b strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.ToLower(scanner.Text()))
+	}
+	return b.String()
+}
+
+// Source is a source of vendored files, along with its license files.
+type Source struct {
+	// name of the source, e.g. the module path or repository URL
+	Name string
+	// optional version of the source, e.g. the module version or git ref
+	Version string
+	Files   []File
+}
+
+// File is a license, notice or copying file of a source.
+type File struct {
+	// name of the file, e.g. LICENSE
+	Name string
+	// the SPDX identifier of the license, empty for notices and unrecognized licenses
+	SPDX    string
+	Content []byte
+}
+
+// ReadSource reads the license files found at the top level of dir.
+func ReadSource(name, version, dir string) (Source, error) {
+	source := Source{Name: name, Version: version}
+	files, err := Find(dir)
+	if err != nil {
+		return source, err
+	}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return source, err
+		}
+		source.Files = append(source.Files, File{
+			Name:    filepath.Base(file),
+			SPDX:    Detect(content),
+			Content: content,
+		})
+	}
+	return source, nil
+}
+
+// Licenses returns the SPDX identifiers of the licenses of the source, without duplicates.
+func (s Source) Licenses() []string {
+	var licenses []string
+	seen := map[string]bool{}
+	for _, file := range s.Files {
+		if file.SPDX != "" && !seen[file.SPDX] {
+			seen[file.SPDX] = true
+			licenses = append(licenses, file.SPDX)
+		}
+	}
+	return licenses
+}
@@ -0,0 +1,71 @@
+package license_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/pkg/license"
+)
+
+const mitLicense = `MIT License
+
+Copyright (c) 2020 Solo.io
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.
+`
+
+var _ = Describe("License", func() {
+	It("finds the top level license files", func() {
+		dir := GinkgoT().TempDir()
+		for _, name := range []string{"LICENSE", "license.md", "NOTICE.txt", "COPYING", "LICENSES_ARE_GREAT.go", "README.md"} {
+			Expect(os.WriteFile(filepath.Join(dir, name), nil, 0644)).NotTo(HaveOccurred())
+		}
+		Expect(os.MkdirAll(filepath.Join(dir, "LICENSES"), os.ModePerm)).NotTo(HaveOccurred())
+
+		files, err := license.Find(dir)
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, file := range files {
+			names = append(names, filepath.Base(file))
+		}
+		Expect(names).To(ConsistOf("LICENSE", "license.md", "NOTICE.txt", "COPYING"))
+	})
+	DescribeTable("detects the SPDX identifier",
+		func(text, spdx string) {
+			Expect(license.Detect([]byte(text))).To(Equal(spdx))
+		},
+		Entry("an identifier line", "// SPDX-License-Identifier: Apache-2.0 OR MIT\n", "Apache-2.0 OR MIT"),
+		Entry("apache", "                                 Apache License\n                           Version 2.0, January 2004\n", "Apache-2.0"),
+		Entry("mit", mitLicense, "MIT"),
+		Entry("bsd 3 clause", "Redistribution and use in source and binary\nforms, with or without modification...\n"+
+			"Neither the name of the copyright holder", "BSD-3-Clause"),
+		Entry("bsd 2 clause", "Redistribution and use in source and binary forms, with or without modification", "BSD-2-Clause"),
+		Entry("mpl", "Mozilla Public License Version 2.0\n", "MPL-2.0"),
+		Entry("unknown", "All rights reserved.\n", ""),
+	)
+	It("writes the aggregated notices", func() {
+		var b strings.Builder
+		Expect(license.WriteNotices(&b, []license.Source{
+			{Name: "github.com/b/b", Version: "v1.0.0"},
+			{
+				Name: "github.com/a/a",
+				Files: []license.File{
+					{Name: "LICENSE", SPDX: "MIT", Content: []byte(mitLicense)},
+					{Name: "NOTICE", Content: []byte("a notice")},
+				},
+			},
+		})).NotTo(HaveOccurred())
+		separator, fileSeparator := strings.Repeat("=", 80), strings.Repeat("-", 80)
+		Expect(b.String()).To(Equal(
+			"This file lists the licenses and notices of the third party sources vendored by anyvendor.\n" +
+				"\n" + separator + "\ngithub.com/a/a\nLicense: MIT\n" +
+				fileSeparator + "\nLICENSE:\n\n" + mitLicense +
+				fileSeparator + "\nNOTICE:\n\na notice\n" +
+				"\n" + separator + "\ngithub.com/b/b v1.0.0\nLicense: no license files found\n"))
+	})
+})
@@ -0,0 +1,55 @@
+package license
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var (
+	sourceSeparator = strings.Repeat("=", 80)
+	fileSeparator   = strings.Repeat("-", 80)
+)
+
+/*
+WriteNotices writes the aggregated notices of every source, sorted by name, to w. Each source is listed with
+its version and licenses, followed by the contents of each of its license files. Sources without any license
+files are listed as such, so they can be followed up on.
+*/
+func WriteNotices(w io.Writer, sources []Source) error {
+	sorted := make([]Source, len(sources))
+	copy(sorted, sources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var b bytes.Buffer
+	b.WriteString("This file lists the licenses and notices of the third party sources vendored by anyvendor.\n")
+	for _, source := range sorted {
+		fmt.Fprintf(&b, "\n%s\n%s", sourceSeparator, source.Name)
+		if source.Version != "" {
+			fmt.Fprintf(&b, " %s", source.Version)
+		}
+		b.WriteByte('\n')
+		licenses := source.Licenses()
+		switch {
+		case len(source.Files) == 0:
+			b.WriteString("License: no license files found\n")
+		case len(licenses) == 0:
+			b.WriteString("License: unknown\n")
+		default:
+			fmt.Fprintf(&b, "License: %s\n", strings.Join(licenses, ", "))
+		}
+		for _, file := range source.Files {
+			fmt.Fprintf(&b, "%s\n%s:\n\n", fileSeparator, file.Name)
+			b.Write(file.Content)
+			if len(file.Content) > 0 && file.Content[len(file.Content)-1] != '\n' {
+				b.WriteByte('\n')
+			}
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"github.com/spf13/afero"
+)
+
+// the contents of the proto files in the vendor dir, keyed by their absolute path
+type vendoredSnapshot map[string][]byte
+
+/*
+read the proto files vendored the last time the modules were vendored, as listed in their metadata files, before
+they are overwritten. Files anyvendor did not vendor for these modules, such as those vendored by other configs
+sharing the vendor dir, or left behind by hand, are not part of the snapshot.
+*/
+func (m *goModFactory) snapshotVendoredProtos(modules []*moduleWithImports) (vendoredSnapshot, error) {
+	snapshot := vendoredSnapshot{}
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	for _, mod := range modules {
+		metadata, err := provenance.ReadMetadata(m.fs, m.vendorRoot(mod))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, file := range metadata.Files {
+			path := filepath.Join(vendorDir, filepath.FromSlash(file.Vendored))
+			if filepath.Ext(path) != ".proto" || !strings.HasPrefix(path, vendorDir+string(filepath.Separator)) {
+				continue
+			}
+			content, err := afero.ReadFile(m.fs, path)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			snapshot[path] = content
+		}
+	}
+	return snapshot, nil
+}
+
+// compare the previously vendored proto files with the vendored files
+func (m *goModFactory) checkBreakingChanges(
+	ctx context.Context,
+	modules []*moduleWithImports,
+	previous vendoredSnapshot,
+	settings *anyvendor.BreakingChanges,
+	extraRoots []string,
+) error {
+	if len(previous) == 0 {
+		return nil
+	}
+	includeRoots := m.includeRoots(modules, extraRoots)
+	currentFiles, names, err := m.protoNames(modules, includeRoots)
+	if err != nil {
+		return m.breakingChangesError(settings, err)
+	}
+
+	vendored := map[string]bool{}
+	for _, file := range currentFiles {
+		vendored[filepath.Clean(file)] = true
+	}
+
+	// the previous files are named like the vendored files, so the same file has the same name in both sets
+	var previousFiles []string
+	for path := range previous {
+		if name, ok := names[filepath.Clean(path)]; ok {
+			previousFiles = append(previousFiles, name)
+		} else if importPath, ok := protoutils.ImportPath(path, includeRoots); ok {
+			previousFiles = append(previousFiles, importPath)
+		}
+	}
+	previousSet, err := protoutils.CompileDescriptorSet(ctx, previousFiles, protoutils.DescriptorSetOptions{
+		IncludeRoots: includeRoots,
+		Accessor: func(path string) (io.ReadCloser, error) {
+			if content, ok := previous[path]; ok {
+				return ioutil.NopCloser(bytes.NewReader(content)), nil
+			}
+			if vendored[filepath.Clean(path)] {
+				// the file was not in the vendor dir before vendoring
+				return nil, os.ErrNotExist
+			}
+			// files the modules do not vendor, such as those of other configs, are unchanged
+			return m.fs.Open(path)
+		},
+	})
+	if err != nil {
+		return m.breakingChangesError(settings, eris.Wrap(err, "unable to compile the previously vendored protos"))
+	}
+	var current []string
+	for _, file := range currentFiles {
+		if name, ok := names[file]; ok {
+			current = append(current, name)
+		}
+	}
+	currentSet, err := protoutils.CompileDescriptorSet(ctx, current, protoutils.DescriptorSetOptions{
+		IncludeRoots: includeRoots,
+		Accessor: func(path string) (io.ReadCloser, error) {
+			return m.fs.Open(path)
+		},
+	})
+	if err != nil {
+		return m.breakingChangesError(settings, eris.Wrap(err, "unable to compile the vendored protos"))
+	}
+
+	changes := protoutils.BreakingChanges(previousSet, currentSet)
+	if len(changes) == 0 {
+		return nil
+	}
+	return m.breakingChangesError(settings, &protoutils.BreakingChangesError{Changes: changes})
+}
+
+// returns the error in strict mode, otherwise it is logged
+func (m *goModFactory) breakingChangesError(settings *anyvendor.BreakingChanges, err error) error {
+	if settings.GetStrict() {
+		return err
+	}
+	redact.Logf("warning: %v", err)
+	return nil
+}
@@ -3,7 +3,6 @@ package manager
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +11,9 @@ import (
 	"github.com/mattn/go-zglob"
 	"github.com/rotisserie/eris"
 	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"github.com/solo-io/anyvendor/pkg/transform"
 	"github.com/spf13/afero"
 )
 
@@ -103,6 +105,9 @@ var (
 	IrregularFileError = func(file string) error {
 		return eris.Errorf("%s is not a regular file", file)
 	}
+	TransformedPathError = func(file, path string) error {
+		return eris.Errorf("transformed path %s of %s is outside of the vendored root", path, file)
+	}
 )
 
 func NewDefaultCopier() *copier {
@@ -121,7 +126,7 @@ func (c *copier) Copy(src, dst string) (int64, error) {
 		return 0, nil
 	}
 
-	log.Printf("copying %v -> %v", src, dst)
+	redact.Logf("copying %v -> %v", src, dst)
 
 	if err := c.fs.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
 		return 0, err
@@ -150,3 +155,69 @@ func (c *copier) Copy(src, dst string) (int64, error) {
 
 	return io.Copy(dstFile, srcFile)
 }
+
+/*
+TransformFile copies src into root, running the transformer on its contents and on relativePath, the path of the
+file relative to root. It returns the path the transformed file was written to.
+*/
+func TransformFile(fs afero.Fs, src, root, relativePath string, transformer transform.Transformer) (string, error) {
+	srcStat, err := fs.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	if !srcStat.Mode().IsRegular() {
+		return "", IrregularFileError(src)
+	}
+	content, err := afero.ReadFile(fs, src)
+	if err != nil {
+		return "", err
+	}
+	transformedPath, transformed, err := transformer.Transform(filepath.ToSlash(relativePath), content)
+	if err != nil {
+		return "", eris.Wrapf(err, "unable to transform %s", src)
+	}
+	transformedPath = filepath.Clean(filepath.FromSlash(transformedPath))
+	if filepath.IsAbs(transformedPath) || transformedPath == ".." ||
+		strings.HasPrefix(transformedPath, ".."+string(filepath.Separator)) {
+		return "", TransformedPathError(src, transformedPath)
+	}
+	dst := filepath.Join(root, transformedPath)
+
+	redact.Logf("copying %v -> %v (transformed)", src, dst)
+
+	if err := fs.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return "", err
+	}
+	if err := afero.WriteFile(fs, dst, transformed, srcStat.Mode().Perm()); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+/*
+StampProvenance prepends the provenance header to the vendored file, replacing any header it already has. The
+header of files which cannot contain comments, such as json, is written to a sidecar file instead, and other files
+are left as they are.
+*/
+func StampProvenance(fs afero.Fs, vendoredFile string, header provenance.Header) error {
+	if provenance.NeedsSidecar(vendoredFile) {
+		sidecar, err := provenance.Sidecar(header)
+		if err != nil {
+			return err
+		}
+		return afero.WriteFile(fs, vendoredFile+provenance.SidecarSuffix, sidecar, 0644)
+	}
+	stat, err := fs.Stat(vendoredFile)
+	if err != nil {
+		return err
+	}
+	content, err := afero.ReadFile(fs, vendoredFile)
+	if err != nil {
+		return err
+	}
+	stamped := provenance.Stamp(vendoredFile, content, header)
+	if string(stamped) == string(content) {
+		return nil
+	}
+	return afero.WriteFile(fs, vendoredFile, stamped, stat.Mode().Perm())
+}
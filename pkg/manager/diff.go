@@ -0,0 +1,176 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/modutils"
+	"github.com/solo-io/anyvendor/pkg/patch"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/spf13/afero"
+)
+
+const devNull = "/dev/null"
+
+/*
+write a unified diff of the files currently vendored from the module against the files which would be vendored
+from the candidate version of it to w. Every step of the config which changes the vendored files is run on the
+candidate in memory.
+*/
+func (m *goModFactory) diffCandidate(ctx context.Context, opts *anyvendor.Config, module, version string, w io.Writer) error {
+	gatherOpts := m.gatherOptions(opts)
+	mods, err := m.gather(gatherOpts)
+	if err != nil {
+		return err
+	}
+	var current *moduleWithImports
+	for _, mod := range mods {
+		if mod.module.Path == module && !mod.module.Main {
+			current = mod
+		}
+	}
+	if current == nil {
+		return eris.Errorf("%s is not vendored by any of the configured imports", module)
+	}
+
+	download, err := modutils.DownloadModuleIn(m.WorkingDirectory, module, version)
+	if err != nil {
+		return err
+	}
+	candidate, err := m.handleSingleModule(&modutils.Module{
+		Path:    download.Path,
+		Version: download.Version,
+		Dir:     download.Dir,
+	}, gatherOpts.MatchOptions)
+	if err != nil {
+		return err
+	}
+	// the modules as they would be vendored with the candidate in place of the current version
+	candidateMods := []*moduleWithImports{candidate}
+	modules := []*modutils.Module{candidate.module}
+	for _, mod := range cloneModules(mods) {
+		if mod.module.Path != module || mod.module.Main {
+			candidateMods = append(candidateMods, mod)
+			modules = append(modules, mod.module)
+		}
+	}
+	imports := m.importResolver(modules)
+	if candidate.transformer, err = m.moduleTransformer(candidate.module, gatherOpts.MatchOptions, imports); err != nil {
+		return err
+	}
+	if gatherOpts.ResolveImports {
+		if err := m.resolveImports(candidateMods, gatherOpts.SkipWellKnownImports); err != nil {
+			return err
+		}
+	}
+	if !gatherOpts.SkipLicenses {
+		if err := m.addLicenseFiles(candidate); err != nil {
+			return err
+		}
+	}
+
+	vendored, _, err := m.vendorInSandbox(ctx, opts, gatherOpts, cloneModules(mods), current.module.Path)
+	if err != nil {
+		return err
+	}
+	candidateFiles := map[string][]byte{}
+	candidatePaths, sandbox, err := m.vendorInSandbox(ctx, opts, gatherOpts, candidateMods, candidate.module.Path)
+	if err != nil {
+		return err
+	}
+	for _, destination := range candidatePaths {
+		if candidateFiles[destination], err = afero.ReadFile(sandbox.fs, destination); err != nil {
+			return err
+		}
+	}
+	return DiffVendored(m.fs, m.vendorRoot(current), vendored, candidateFiles, w)
+}
+
+/*
+vendor the modules in a sandbox, and return the destinations of the files vendored from the module, other than
+those deleted by a patch, along with the sandbox
+*/
+func (m *goModFactory) vendorInSandbox(
+	ctx context.Context,
+	opts *anyvendor.Config,
+	gatherOpts goModOptions,
+	mods []*moduleWithImports,
+	module string,
+) ([]string, *goModFactory, error) {
+	sandbox := m.sandbox()
+	if err := sandbox.vendor(ctx, opts, gatherOpts, mods); err != nil {
+		return nil, nil, err
+	}
+	var destinations []string
+	for _, mod := range mods {
+		if mod.module.Path != module || mod.module.Main {
+			continue
+		}
+		for _, vendorFile := range mod.vendorList {
+			destination := sandbox.destination(mod, vendorFile)
+			if _, err := sandbox.fs.Stat(destination); os.IsNotExist(err) {
+				// deleted by a patch
+				continue
+			} else if err != nil {
+				return nil, nil, err
+			}
+			destinations = append(destinations, destination)
+		}
+	}
+	return destinations, sandbox, nil
+}
+
+/*
+DiffVendored writes a unified diff of the vendored files, and the destinations of the candidate files, against
+the contents of the candidate files to w. Vendored files which are not candidates are shown as deleted. Files are
+named relative to root, and provenance headers are ignored on both sides.
+*/
+func DiffVendored(fs afero.Fs, root string, vendored []string, candidate map[string][]byte, w io.Writer) error {
+	destinations := map[string]bool{}
+	for _, destination := range vendored {
+		destinations[destination] = true
+	}
+	for destination := range candidate {
+		destinations[destination] = true
+	}
+	var sorted []string
+	for destination := range destinations {
+		sorted = append(sorted, destination)
+	}
+	sort.Strings(sorted)
+
+	for _, destination := range sorted {
+		existing, err := afero.ReadFile(fs, destination)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		existing = provenance.Strip(destination, existing)
+		name := diffName(root, destination)
+		oldName, newName := name, name
+		if os.IsNotExist(err) {
+			oldName = devNull
+		}
+		content, ok := candidate[destination]
+		if !ok {
+			newName = devNull
+		}
+		content = provenance.Strip(destination, content)
+		if _, err := io.WriteString(w, patch.Diff(oldName, newName, existing, content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns the name of the vendored file in a diff, relative to the vendored root of its module
+func diffName(root, destination string) string {
+	if relative, err := filepath.Rel(root, destination); err == nil {
+		return filepath.ToSlash(relative)
+	}
+	return filepath.ToSlash(destination)
+}
@@ -3,14 +3,24 @@ package manager
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	protov1 "github.com/golang/protobuf/proto"
 	"github.com/rotisserie/eris"
 	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/license"
 	"github.com/solo-io/anyvendor/pkg/modutils"
+	"github.com/solo-io/anyvendor/pkg/policy"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"github.com/solo-io/anyvendor/pkg/transform"
 	"github.com/spf13/afero"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -19,14 +29,27 @@ var (
 )
 
 type goModOptions struct {
-	MatchOptions  []*anyvendor.GoModImport
-	LocalMatchers []string
+	MatchOptions         []*anyvendor.GoModImport
+	LocalMatchers        []string
+	ResolveImports       bool
+	SkipWellKnownImports bool
+	SkipLicenses         bool
+	// the policy of the settings of the config, enforced along with the policy of the factory
+	Policy *anyvendor.Policy
+	// do not enforce any policy, when the modules are only listed rather than vendored
+	SkipPolicy bool
 }
 
 // struct which represents a go module package in the module package list
 type moduleWithImports struct {
-	module     *modutils.Module
-	vendorList []string // files to vendor
+	module      *modutils.Module
+	vendorList  []string // files to vendor
+	patterns    []string // the patterns which matched the files
+	transformer transform.Transformer
+	// destinations of the vendored files which were moved by the transformer
+	renamed map[string]string
+	// the license files of the module, which are vendored verbatim
+	licenses map[string]bool
 }
 
 func NewGoModFactory(settings *anyvendor.FactorySettings) (*goModFactory, error) {
@@ -43,6 +66,8 @@ func NewGoModFactory(settings *anyvendor.FactorySettings) (*goModFactory, error)
 		WorkingDirectory: cwd,
 		fs:               fs,
 		fileCopier:       NewCopier(fs, settings.GetSkipPatterns()),
+		skipPatterns:     settings.GetSkipPatterns(),
+		policy:           settings.GetPolicy(),
 	}, nil
 }
 
@@ -51,36 +76,160 @@ type goModFactory struct {
 	packageName      bool
 	fs               afero.Fs
 	fileCopier       FileCopier
+	skipPatterns     []string
+	transformers     []moduleTransformer
+	policy           *anyvendor.Policy
 }
 
+/*
+a transformer registered from go, for module and the modules nested under it, or every module when it is empty, see
+importsModule. It never runs on the local module.
+*/
+type moduleTransformer struct {
+	module      string
+	transformer transform.Transformer
+}
+
+// vendor the modules, leaving any stale files in place, see Manager.Apply
 func (m *goModFactory) Ensure(ctx context.Context, opts *anyvendor.Config) error {
-	var packages []*anyvendor.GoModImport
-	for _, cfg := range opts.Imports {
-		if cfg.GetGoMod() != nil {
-			packages = append(packages, cfg.GetGoMod())
+	plan, err := m.resolve(opts)
+	if err != nil {
+		return err
+	}
+	return m.apply(ctx, plan)
+}
+
+// vendor the modules resolved by the plan, and delete the files it found to be stale
+func (m *goModFactory) apply(ctx context.Context, plan *Plan) error {
+	opts, gatherOpts, mods := plan.opts, plan.gatherOpts, plan.modules
+
+	var (
+		previous vendoredSnapshot
+		err      error
+	)
+	if opts.GetBreakingChanges() != nil {
+		if previous, err = m.snapshotVendoredProtos(mods); err != nil {
+			return err
 		}
 	}
-	mods, err := m.gather(goModOptions{
-		MatchOptions:  packages,
-		LocalMatchers: opts.GetLocal().GetPatterns(),
-	})
-	if err != nil {
+
+	if err := m.deleteStaleFiles(plan); err != nil {
 		return err
 	}
 
-	err = m.copy(mods)
-	if err != nil {
+	if err := m.vendor(ctx, opts, gatherOpts, mods); err != nil {
+		return err
+	}
+
+	if opts.GetValidateImports() != nil {
+		if err := m.validateImports(mods, opts.GetValidateImports()); err != nil {
+			return err
+		}
+	}
+
+	if opts.GetDescriptorSet() != nil {
+		if err := m.writeDescriptorSet(ctx, mods, opts.GetDescriptorSet(), opts.GetValidateImports().GetExtraRoots()); err != nil {
+			return err
+		}
+	}
+
+	if opts.GetBreakingChanges() != nil {
+		err := m.checkBreakingChanges(ctx, mods, previous, opts.GetBreakingChanges(), opts.GetValidateImports().GetExtraRoots())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+copy the files of the modules into the vendor dir, and run every step of the config which changes them, along with
+the notices and metadata files written alongside them
+*/
+func (m *goModFactory) vendor(
+	ctx context.Context,
+	opts *anyvendor.Config,
+	gatherOpts goModOptions,
+	mods []*moduleWithImports,
+) error {
+	if err := m.copy(mods); err != nil {
+		return err
+	}
+
+	if !opts.GetSkipLicenses() {
+		if err := m.writeNotices(mods); err != nil {
+			return err
+		}
+	}
+
+	if err := m.applyPatches(mods, gatherOpts.MatchOptions); err != nil {
 		return err
 	}
+
+	if err := m.shadeModules(ctx, mods, gatherOpts.MatchOptions); err != nil {
+		return err
+	}
+
+	if mapping := importMapping(opts.GetPatch()); mapping != nil {
+		if err := m.rewriteImports(mods, mapping); err != nil {
+			return err
+		}
+		if opts.GetPatch().GetRewriteLocal() {
+			if err := m.rewriteLocalImports(mods, mapping); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rules := goPackageRules(opts.GetPatch()); len(rules) > 0 {
+		var err error
+		if mapping := opts.GetPatch().GetGoPackageMapping(); mapping != nil {
+			err = m.writeGoPackageMapping(mods, rules, mapping)
+		} else {
+			err = m.applyGoPackageRules(mods, rules)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.GetProvenanceHeaders() {
+		if err := m.stampProvenance(mods); err != nil {
+			return err
+		}
+	}
+
+	if !opts.GetSkipMetadata() {
+		if err := m.writeMetadata(mods); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (m *goModFactory) gatherOptions(opts *anyvendor.Config) goModOptions {
+	var packages []*anyvendor.GoModImport
+	for _, cfg := range opts.Imports {
+		if cfg.GetGoMod() != nil {
+			packages = append(packages, cfg.GetGoMod())
+		}
+	}
+	return goModOptions{
+		MatchOptions:         packages,
+		LocalMatchers:        opts.GetLocal().GetPatterns(),
+		ResolveImports:       opts.GetResolveImports(),
+		SkipWellKnownImports: opts.GetSkipWellKnownImports(),
+		SkipLicenses:         opts.GetSkipLicenses(),
+		Policy:               opts.GetSettings().GetPolicy(),
+	}
+}
+
 // gather up all packages for a given go module
 // currently this function uses the cmd `go list -m all` to figure out the list of dep
 // all of the logic surrounding go.mod and the go cli calls are in the modutils package
 func (m *goModFactory) gather(opts goModOptions) ([]*moduleWithImports, error) {
 	// Ensure go.mod file exists and we're running from the project root,
-	modPackageFile, err := modutils.GetCurrentModPackageFile()
+	modPackageFile, err := modutils.GetModPackageFile(m.WorkingDirectory)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +244,7 @@ func (m *goModFactory) gather(opts goModOptions) ([]*moduleWithImports, error) {
 		moduleNames = append(moduleNames, v.Package)
 	}
 	moduleNames = append([]string{packageName}, moduleNames...)
-	modPackages, err := modutils.GetCurrentPackageListJson(moduleNames)
+	modPackages, err := modutils.GetPackageListJson(m.WorkingDirectory, moduleNames)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +258,7 @@ func (m *goModFactory) gather(opts goModOptions) ([]*moduleWithImports, error) {
 	}
 
 	var modules []*moduleWithImports
+	resolver := m.importResolver(modPackages)
 	// handle all packages
 	for _, modPackage := range modPackages {
 		imports := opts.MatchOptions
@@ -119,12 +269,224 @@ func (m *goModFactory) gather(opts goModOptions) ([]*moduleWithImports, error) {
 		if err != nil {
 			return nil, err
 		}
-		if len(mod.vendorList) > 0 {
-			modules = append(modules, mod)
+		if mod.transformer, err = m.moduleTransformer(modPackage, imports, resolver); err != nil {
+			return nil, err
+		}
+		modules = append(modules, mod)
+	}
+
+	if opts.ResolveImports {
+		if err := m.resolveImports(modules, opts.SkipWellKnownImports); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []*moduleWithImports
+	for _, mod := range modules {
+		if len(mod.vendorList) == 0 {
+			continue
+		}
+		if !opts.SkipLicenses && !mod.module.Main {
+			if err := m.addLicenseFiles(mod); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, mod)
+	}
+	if !opts.SkipPolicy {
+		if err := m.evaluatePolicy(result, opts.Policy); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+/*
+check every module, other than the local one, against the policy of the factory, and the policy of the settings of
+the config, before anything is vendored
+*/
+func (m *goModFactory) evaluatePolicy(modules []*moduleWithImports, configPolicy *anyvendor.Policy) error {
+	policies := []*anyvendor.Policy{m.policy}
+	if !protov1.Equal(configPolicy, m.policy) {
+		policies = append(policies, configPolicy)
+	}
+	var sources []policy.Source
+	for _, mod := range modules {
+		if mod.module.Main {
+			continue
+		}
+		sources = append(sources, policy.Source{
+			Name:  mod.module.Path,
+			Dir:   mod.module.Dir,
+			Files: mod.vendorList,
+		})
+	}
+	for _, p := range policies {
+		if p == nil {
+			continue
+		}
+		if err := policy.Evaluate(p, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vendor the top level license files of the module along with its files, they are never transformed, patched or stamped
+func (m *goModFactory) addLicenseFiles(mod *moduleWithImports) error {
+	licenseFiles, err := license.Find(mod.module.Dir)
+	if err != nil {
+		return err
+	}
+	for _, licenseFile := range licenseFiles {
+		if !containsString(mod.vendorList, licenseFile) {
+			mod.vendorList = append(mod.vendorList, licenseFile)
+		}
+		if mod.licenses == nil {
+			mod.licenses = map[string]bool{}
 		}
+		mod.licenses[licenseFile] = true
 	}
+	return nil
+}
 
-	return modules, nil
+// write the aggregated notices of every vendored module to the vendor dir
+func (m *goModFactory) writeNotices(modules []*moduleWithImports) error {
+	var sources []license.Source
+	for _, mod := range modules {
+		if mod.module.Main {
+			continue
+		}
+		source, err := license.ReadSource(mod.module.Path, mod.module.Version, mod.module.Dir)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return m.writeOutputFile(filepath.Join(anyvendor.DefaultDepDir, license.NoticesFile), func(w io.Writer) error {
+		return license.WriteNotices(w, sources)
+	})
+}
+
+var majorVersionRegex = regexp.MustCompile(`^v[0-9]+$`)
+
+/*
+returns true if the module is the package of an import, or is nested within it. A module with a major version
+suffix, such as github.com/solo-io/api/v2, is another major version of the package, not nested within it. An empty
+package matches every module.
+*/
+func importsModule(pkg, module string) bool {
+	if pkg == "" || module == pkg {
+		return true
+	}
+	if !strings.HasPrefix(module, pkg+"/") {
+		return false
+	}
+	nested := strings.SplitN(strings.TrimPrefix(module, pkg+"/"), "/", 2)[0]
+	return !majorVersionRegex.MatchString(nested)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// add the files needed to satisfy the imports of every vendored proto to the vendor lists of the modules
+// which contain them
+func (m *goModFactory) resolveImports(modules []*moduleWithImports, skipWellKnownImports bool) error {
+	var (
+		files   []string
+		sources []protoutils.ImportSource
+	)
+	for _, mod := range modules {
+		files = append(files, mod.vendorList...)
+		dir := mod.module.Dir
+		if mod.module.Main {
+			// local files are vendored relative to the working directory
+			dir = m.WorkingDirectory
+		}
+		sources = append(sources, protoutils.ImportSource{
+			Dir:    dir,
+			Prefix: mod.module.Path,
+		})
+	}
+	closure, err := protoutils.ImportClosure(files, sources, protoutils.ClosureOptions{
+		Fs:                   m.fs,
+		SkipWellKnownImports: skipWellKnownImports,
+	})
+	if err != nil {
+		return err
+	}
+	if len(closure.SkippedWellKnownImports) > 0 {
+		redact.Logf("skipped well known imports, which must be provided by protoc: %s",
+			strings.Join(closure.SkippedWellKnownImports, ", "))
+	}
+	for _, file := range closure.Imported {
+		mod := modules[file.Source]
+		mod.vendorList = append(mod.vendorList, file.Path)
+	}
+	return nil
+}
+
+// chain the configured transforms of every import matching the module, followed by those registered from go
+func (m *goModFactory) moduleTransformer(
+	module *modutils.Module,
+	matchOptions []*anyvendor.GoModImport,
+	imports protoutils.ImportResolver,
+) (transform.Transformer, error) {
+	var chain transform.Chain
+	for _, matchOpt := range matchOptions {
+		if len(matchOpt.GetTransforms()) == 0 || !importsModule(matchOpt.GetPackage(), module.Path) {
+			continue
+		}
+		transforms, err := transform.FromConfig(matchOpt.GetTransforms(), imports)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid transforms for %s", matchOpt.GetPackage())
+		}
+		chain = append(chain, transforms...)
+	}
+	for _, registered := range m.transformers {
+		if !module.Main && importsModule(registered.module, module.Path) {
+			chain = append(chain, registered.transformer)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
+
+/*
+resolve the imports of vendored proto files against the source of the modules, so their packages can be read. An
+import prefixed with the path of a module is looked up beneath its directory, then any import is looked up relative
+to the directory of each module, and finally in the vendor dir.
+*/
+func (m *goModFactory) importResolver(modules []*modutils.Module) protoutils.ImportResolver {
+	return func(importPath string) ([]byte, bool) {
+		var candidates []string
+		for _, module := range modules {
+			if strings.HasPrefix(importPath, module.Path+"/") {
+				candidates = append(candidates, filepath.Join(module.Dir, strings.TrimPrefix(importPath, module.Path+"/")))
+			}
+		}
+		for _, module := range modules {
+			candidates = append(candidates, filepath.Join(module.Dir, importPath))
+		}
+		candidates = append(candidates, filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir, importPath))
+		for _, candidate := range candidates {
+			if b, err := afero.ReadFile(m.fs, filepath.FromSlash(candidate)); err == nil {
+				return b, true
+			}
+		}
+		return nil, false
+	}
 }
 
 func (m *goModFactory) handleSingleModule(module *modutils.Module, matchOptions []*anyvendor.GoModImport) (*moduleWithImports, error) {
@@ -144,14 +506,17 @@ func (m *goModFactory) handleSingleModule(module *modutils.Module, matchOptions
 		return &moduleWithImports{
 			module:     module,
 			vendorList: vendorList,
+			patterns:   DefaultMatchPatterns,
 		}, nil
 	}
 
-	var result []string
+	var (
+		result   []string
+		patterns []string
+	)
 	for _, matchOpt := range matchOptions {
 		// only check module if is in imports list, or imports list in empty
-		if len(matchOpt.Package) != 0 &&
-			!strings.Contains(module.Path, matchOpt.Package) {
+		if !importsModule(matchOpt.Package, module.Path) {
 			continue
 		}
 		// Build list of files to module path source to project vendor folder
@@ -160,35 +525,218 @@ func (m *goModFactory) handleSingleModule(module *modutils.Module, matchOptions
 			return nil, err
 		}
 		result = append(result, vendorList...)
+		patterns = append(patterns, matchOpt.Patterns...)
 	}
 	return &moduleWithImports{
 		module:     module,
 		vendorList: result,
+		patterns:   patterns,
 	}, nil
 }
 
 func (m *goModFactory) copy(modules []*moduleWithImports) error {
 	// Copy mod vendor list files to ./vendor/
 	for _, mod := range modules {
-		if mod.module.Main == true {
-			for _, vendorFile := range mod.vendorList {
-				localPath := strings.TrimPrefix(vendorFile, m.WorkingDirectory+"/")
-				localFile := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir, mod.module.Path, localPath)
-				if _, err := m.fileCopier.Copy(vendorFile, localFile); err != nil {
-					return eris.Wrap(err, fmt.Sprintf("Error! %s - unable to copy file %s\n",
-						err.Error(), vendorFile))
+		for _, vendorFile := range mod.vendorList {
+			if mod.transformer != nil && !mod.licenses[vendorFile] {
+				localFile, err := TransformFile(m.fs, vendorFile, m.vendorRoot(mod), m.relativePath(mod, vendorFile),
+					mod.transformer)
+				if err != nil {
+					return err
 				}
-			}
-		} else {
-			for _, vendorFile := range mod.vendorList {
-				localPath := filepath.Join(mod.module.Path, vendorFile[len(mod.module.Dir):])
-				localFile := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir, localPath)
-				if _, err := m.fileCopier.Copy(vendorFile, localFile); err != nil {
-					return eris.Wrap(err, fmt.Sprintf("Error! %s - unable to copy file %s\n",
-						err.Error(), vendorFile))
+				if mod.renamed == nil {
+					mod.renamed = map[string]string{}
 				}
+				mod.renamed[vendorFile] = localFile
+				continue
+			}
+			localFile := m.destination(mod, vendorFile)
+			if _, err := m.fileCopier.Copy(vendorFile, localFile); err != nil {
+				return eris.Wrap(err, fmt.Sprintf("Error! %s - unable to copy file %s\n",
+					err.Error(), vendorFile))
 			}
 		}
 	}
 	return nil
 }
+
+// stamp every file vendored from another module with the module, version and path it was vendored from
+func (m *goModFactory) stampProvenance(modules []*moduleWithImports) error {
+	version := provenance.Version()
+	for _, mod := range modules {
+		if mod.module.Main {
+			continue
+		}
+		for _, vendorFile := range mod.vendorList {
+			if mod.licenses[vendorFile] {
+				continue
+			}
+			localFile := m.destination(mod, vendorFile)
+			if _, err := m.fs.Stat(localFile); os.IsNotExist(err) {
+				// deleted by a patch
+				continue
+			}
+			header := provenance.Header{
+				Source:    mod.module.Path,
+				Version:   mod.module.Version,
+				Path:      filepath.ToSlash(m.relativePath(mod, vendorFile)),
+				Anyvendor: version,
+			}
+			if err := StampProvenance(m.fs, localFile, header); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// write the metadata file, describing the module and its vendored files, to the vendored root of every module
+func (m *goModFactory) writeMetadata(modules []*moduleWithImports) error {
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	version := provenance.Version()
+	for _, mod := range modules {
+		metadata := provenance.Metadata{
+			Type:      provenance.SourceTypeGoMod,
+			Source:    mod.module.Path,
+			Version:   mod.module.Version,
+			Commit:    provenance.ModuleCommit(mod.module.Version),
+			Patterns:  mod.patterns,
+			Anyvendor: version,
+		}
+		if mod.module.Main {
+			metadata.Type = provenance.SourceTypeLocal
+		}
+		for _, vendorFile := range mod.vendorList {
+			localFile := m.destination(mod, vendorFile)
+			hash, err := provenance.HashFile(m.fs, localFile)
+			if os.IsNotExist(err) {
+				// deleted by a patch
+				continue
+			} else if err != nil {
+				return err
+			}
+			vendored, err := filepath.Rel(vendorDir, localFile)
+			if err != nil {
+				return err
+			}
+			metadata.Files = append(metadata.Files, provenance.File{
+				Path:     filepath.ToSlash(m.relativePath(mod, vendorFile)),
+				Vendored: filepath.ToSlash(vendored),
+				SHA256:   hash,
+			})
+		}
+		if err := provenance.WriteMetadata(m.fs, m.vendorRoot(mod), metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns the path in the vendor dir which the vendor file will be copied to
+func (m *goModFactory) destination(mod *moduleWithImports, vendorFile string) string {
+	if renamed, ok := mod.renamed[vendorFile]; ok {
+		return renamed
+	}
+	return filepath.Join(m.vendorRoot(mod), m.relativePath(mod, vendorFile))
+}
+
+// returns the path of the vendor file relative to the root of its module
+func (m *goModFactory) relativePath(mod *moduleWithImports, vendorFile string) string {
+	if mod.module.Main {
+		return strings.TrimPrefix(vendorFile, m.WorkingDirectory+"/")
+	}
+	return strings.TrimPrefix(vendorFile[len(mod.module.Dir):], "/")
+}
+
+// check that the imports of every vendored proto file resolve to exactly one file in the include roots
+func (m *goModFactory) validateImports(modules []*moduleWithImports, validation *anyvendor.ImportValidation) error {
+	var files []string
+	for _, mod := range modules {
+		for _, vendorFile := range mod.vendorList {
+			files = append(files, m.destination(mod, vendorFile))
+		}
+	}
+	return protoutils.ValidateImports(files, m.includeRoots(modules, validation.GetExtraRoots()))
+}
+
+/*
+the include roots of the vendored files, the vendor dir itself and the vendored root of every module, followed by
+the extra roots, which are resolved against the working directory
+*/
+func (m *goModFactory) includeRoots(modules []*moduleWithImports, extraRoots []string) []string {
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	includeRoots := []string{vendorDir}
+	for _, mod := range modules {
+		includeRoots = append(includeRoots, m.vendorRoot(mod))
+	}
+	for _, root := range extraRoots {
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(m.WorkingDirectory, root)
+		}
+		includeRoots = append(includeRoots, root)
+	}
+	return includeRoots
+}
+
+// returns the name every vendored proto file is compiled as, keyed by its path, see protoutils.ImportNames
+func (m *goModFactory) protoNames(modules []*moduleWithImports, includeRoots []string) ([]string, map[string]string, error) {
+	var localFiles []string
+	for _, mod := range modules {
+		for _, vendorFile := range mod.vendorList {
+			localFile := filepath.Clean(m.destination(mod, vendorFile))
+			if filepath.Ext(localFile) == ".proto" {
+				localFiles = append(localFiles, localFile)
+			}
+		}
+	}
+	names, err := protoutils.ImportNames(localFiles, includeRoots)
+	if err != nil {
+		return nil, nil, err
+	}
+	return localFiles, names, nil
+}
+
+// returns the names of every vendored proto file, in the order they were vendored
+func (m *goModFactory) vendoredProtos(modules []*moduleWithImports, includeRoots []string) ([]string, error) {
+	localFiles, names, err := m.protoNames(modules, includeRoots)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, localFile := range localFiles {
+		if name, ok := names[localFile]; ok {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// compile every vendored proto file into a FileDescriptorSet
+func (m *goModFactory) writeDescriptorSet(
+	ctx context.Context,
+	modules []*moduleWithImports,
+	output *anyvendor.DescriptorSet,
+	extraRoots []string,
+) error {
+	includeRoots := m.includeRoots(modules, extraRoots)
+	files, err := m.vendoredProtos(modules, includeRoots)
+	if err != nil {
+		return err
+	}
+	set, err := protoutils.CompileDescriptorSet(ctx, files, protoutils.DescriptorSetOptions{
+		IncludeRoots:      includeRoots,
+		IncludeSourceInfo: output.GetIncludeSourceInfo(),
+		IncludeImports:    output.GetIncludeImports(),
+	})
+	if err != nil {
+		return err
+	}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		return err
+	}
+	return m.writeOutputFile(output.GetPath(), func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +15,13 @@ import (
 	"github.com/solo-io/anyvendor/anyvendor"
 	mock_manager "github.com/solo-io/anyvendor/pkg/manager/mocks"
 	"github.com/solo-io/anyvendor/pkg/modutils"
+	"github.com/solo-io/anyvendor/pkg/patch"
+	"github.com/solo-io/anyvendor/pkg/policy"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/spf13/afero"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 var _ = Describe("anyvendor", func() {
@@ -240,5 +249,946 @@ var _ = Describe("anyvendor", func() {
 			Expect(modules[1].module.Path).To(Equal(EnvoyValidateProtoMatcher.Package))
 			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
 		})
+		It("can transform vendored files", func() {
+			matcher := &anyvendor.GoModImport{
+				Package:  EnvoyValidateProtoMatcher.Package,
+				Patterns: EnvoyValidateProtoMatcher.Patterns,
+				Transforms: []*anyvendor.Transform{
+					{
+						TransformType: &anyvendor.Transform_StripProtoOptions{
+							StripProtoOptions: &anyvendor.StripProtoOptions{Names: []string{"go_package"}},
+						},
+					},
+					{
+						TransformType: &anyvendor.Transform_RegexReplace{
+							RegexReplace: &anyvendor.RegexReplace{Pattern: "^validate/", Replacement: "pgv/", Path: true},
+						},
+					},
+				},
+			}
+			modules, err := mgr.gather(goModOptions{MatchOptions: []*anyvendor.GoModImport{matcher}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modules).To(HaveLen(1))
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, matcher.Package, "pgv", "validate.proto")
+			Expect(mgr.destination(modules[0], modules[0].vendorList[0])).To(Equal(vendored))
+			content, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).NotTo(ContainSubstring("go_package"))
+		})
+		It("can rewrite the imports of relocated files", func() {
+			matcher := &anyvendor.GoModImport{
+				Package:  EnvoyValidateProtoMatcher.Package,
+				Patterns: EnvoyValidateProtoMatcher.Patterns,
+				Transforms: []*anyvendor.Transform{
+					{
+						TransformType: &anyvendor.Transform_RegexReplace{
+							RegexReplace: &anyvendor.RegexReplace{Pattern: "^validate/", Replacement: "pgv/", Path: true},
+						},
+					},
+				},
+			}
+			// files vendored by the other tests would satisfy the imports
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+			modules, err := mgr.gather(goModOptions{
+				MatchOptions:  []*anyvendor.GoModImport{matcher},
+				LocalMatchers: []string{"anyvendor/**/*.proto"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			Expect(mgr.validateImports(modules, &anyvendor.ImportValidation{})).To(HaveOccurred())
+
+			mapping := importMapping(&anyvendor.Patch{
+				ImportRewrites: []*anyvendor.ImportRewrite{{From: "validate/", To: "pgv/"}},
+			})
+			Expect(mgr.rewriteImports(modules, mapping)).NotTo(HaveOccurred())
+			Expect(mgr.validateImports(modules, &anyvendor.ImportValidation{})).NotTo(HaveOccurred())
+			content, err := os.ReadFile(filepath.Join(modPathString, anyvendor.DefaultDepDir, modules[0].module.Path,
+				"anyvendor", "anyvendor.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring(`import "pgv/validate.proto";`))
+		})
+		It("can shade vendored protos", func() {
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+			matcher := &anyvendor.GoModImport{
+				Package:  EnvoyValidateProtoMatcher.Package,
+				Patterns: EnvoyValidateProtoMatcher.Patterns,
+				Shade:    &anyvendor.Shade{PackagePrefix: "pgv", PathPrefix: "pgv"},
+			}
+			modules, err := mgr.gather(goModOptions{MatchOptions: []*anyvendor.GoModImport{matcher}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			Expect(mgr.shadeModules(context.Background(), modules, []*anyvendor.GoModImport{matcher})).NotTo(HaveOccurred())
+
+			shaded := filepath.Join(modPathString, anyvendor.DefaultDepDir, "pgv", "validate", "validate.proto")
+			Expect(mgr.destination(modules[0], modules[0].vendorList[0])).To(Equal(shaded))
+			content, err := os.ReadFile(shaded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("package pgv.validate;"))
+			_, err = os.Stat(filepath.Join(modPathString, anyvendor.DefaultDepDir, matcher.Package, "validate", "validate.proto"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("can compile vendored protos into a descriptor set", func() {
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+			modules, err := mgr.gather(goModOptions{
+				MatchOptions:  []*anyvendor.GoModImport{EnvoyValidateProtoMatcher},
+				LocalMatchers: []string{"anyvendor/**/*.proto"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+
+			output := filepath.Join(GinkgoT().TempDir(), "descriptors.binpb")
+			Expect(mgr.writeDescriptorSet(context.Background(), modules, &anyvendor.DescriptorSet{Path: output}, nil)).NotTo(HaveOccurred())
+			b, err := os.ReadFile(output)
+			Expect(err).NotTo(HaveOccurred())
+			set := &descriptorpb.FileDescriptorSet{}
+			Expect(proto.Unmarshal(b, set)).NotTo(HaveOccurred())
+			var names []string
+			for _, file := range set.GetFile() {
+				names = append(names, file.GetName())
+			}
+			Expect(names).To(Equal([]string{"validate/validate.proto", "anyvendor/anyvendor.proto"}))
+
+			// the vendored files no longer compile
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+			Expect(os.WriteFile(vendored, []byte("syntax = \"proto3\";\nmessage FieldRules {\n  Missing missing = 1;\n}\n"), 0644)).NotTo(HaveOccurred())
+			err = mgr.writeDescriptorSet(context.Background(), modules, &anyvendor.DescriptorSet{Path: output}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("validate/validate.proto:3:3:"))
+		})
+		It("can detect breaking changes to vendored protos", func() {
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+			modules, err := mgr.gather(goModOptions{MatchOptions: []*anyvendor.GoModImport{EnvoyValidateProtoMatcher}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			Expect(mgr.writeMetadata(modules)).NotTo(HaveOccurred())
+
+			// a file vendored by another config, and a file anyvendor did not vendor, are not compared
+			vendorDir := filepath.Join(modPathString, anyvendor.DefaultDepDir)
+			other := filepath.Join(vendorDir, "example.com", "other", "other.proto")
+			stale := filepath.Join(vendorDir, EnvoyValidateProtoMatcher.Package, "validate", "stale.proto")
+			for _, path := range []string{other, stale} {
+				Expect(os.MkdirAll(filepath.Dir(path), 0755)).NotTo(HaveOccurred())
+				Expect(os.WriteFile(path, []byte("syntax = \"proto3\";\nimport \"missing.proto\";\n"), 0644)).NotTo(HaveOccurred())
+			}
+
+			// the previous version had a field which has since been removed
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+			content, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			previousContent := strings.Replace(string(content), "message FieldRules {",
+				"message FieldRules {\n    optional string removed = 100;", 1)
+			Expect(os.WriteFile(vendored, []byte(previousContent), 0644)).NotTo(HaveOccurred())
+			previous, err := mgr.snapshotVendoredProtos(modules)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(previous).To(HaveLen(1))
+
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			err = mgr.checkBreakingChanges(context.Background(), modules, previous, &anyvendor.BreakingChanges{Strict: true}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("validate/validate.proto: FIELD_REMOVED: field FieldRules.removed was removed"))
+			Expect(mgr.checkBreakingChanges(context.Background(), modules, previous, &anyvendor.BreakingChanges{}, nil)).NotTo(HaveOccurred())
+
+			unchanged, err := mgr.snapshotVendoredProtos(modules)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unchanged).To(HaveLen(1))
+			Expect(mgr.checkBreakingChanges(context.Background(), modules, unchanged, &anyvendor.BreakingChanges{Strict: true}, nil)).NotTo(HaveOccurred())
+			Expect(os.RemoveAll(vendorDir)).NotTo(HaveOccurred())
+		})
+		It("can vendor licenses and write notices", func() {
+			modules, err := mgr.gather(goModOptions{MatchOptions: []*anyvendor.GoModImport{EnvoyValidateProtoMatcher}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modules).To(HaveLen(1))
+			Expect(modules[0].vendorList).To(ContainElements(
+				filepath.Join(modules[0].module.Dir, "LICENSE"),
+				filepath.Join(modules[0].module.Dir, "NOTICE"),
+			))
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			Expect(mgr.writeNotices(modules)).NotTo(HaveOccurred())
+
+			notices, err := os.ReadFile(filepath.Join(modPathString, anyvendor.DefaultDepDir, "THIRD_PARTY_NOTICES"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(notices)).To(ContainSubstring(fmt.Sprintf("\n%s %s\nLicense: Apache-2.0\n",
+				modules[0].module.Path, modules[0].module.Version)))
+			_, err = os.Stat(filepath.Join(modPathString, anyvendor.DefaultDepDir, modules[0].module.Path, "LICENSE"))
+			Expect(err).NotTo(HaveOccurred())
+
+			modules, err = mgr.gather(goModOptions{
+				MatchOptions: []*anyvendor.GoModImport{EnvoyValidateProtoMatcher},
+				SkipLicenses: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modules[0].vendorList).To(HaveLen(1))
+		})
+		It("enforces the policy on vendored modules", func() {
+			mgr.policy = &anyvendor.Policy{
+				AllowedSources:  []string{"github.com/envoyproxy"},
+				AllowedLicenses: []string{"Apache-2.0"},
+			}
+			defer func() { mgr.policy = nil }()
+			_, err := mgr.gather(goModOptions{MatchOptions: []*anyvendor.GoModImport{EnvoyValidateProtoMatcher}})
+			Expect(err).NotTo(HaveOccurred())
+
+			mgr.policy = &anyvendor.Policy{
+				AllowedSources:  []string{"github.com/solo-io/**"},
+				AllowedLicenses: []string{"MIT"},
+				DeniedPaths:     []string{"validate/*.proto"},
+			}
+			_, err = mgr.gather(goModOptions{
+				MatchOptions:  []*anyvendor.GoModImport{EnvoyValidateProtoMatcher},
+				LocalMatchers: []string{"anyvendor/**/*.proto"},
+			})
+			Expect(err).To(HaveOccurred())
+			violations, ok := err.(*policy.ViolationsError)
+			Expect(ok).To(BeTrue())
+			Expect(violations.Violations).To(ConsistOf(
+				policy.Violation{Source: EnvoyValidateProtoMatcher.Package, Reason: "source is not allowed"},
+				policy.Violation{Source: EnvoyValidateProtoMatcher.Package, Reason: "license Apache-2.0 is not allowed"},
+				policy.Violation{
+					Source: EnvoyValidateProtoMatcher.Package,
+					File:   "validate/validate.proto",
+					Reason: "path is denied by validate/*.proto",
+				},
+			))
+		})
+		It("enforces the policy in the settings of the config", func() {
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: EnvoyValidateProtoMatcher}},
+				},
+				Settings: &anyvendor.FactorySettings{
+					Policy: &anyvendor.Policy{AllowedSources: []string{"github.com/solo-io/**"}},
+				},
+			}
+			_, err := mgr.gather(mgr.gatherOptions(cfg))
+			Expect(err).To(HaveOccurred())
+			violations, ok := err.(*policy.ViolationsError)
+			Expect(ok).To(BeTrue())
+			Expect(violations.Violations).To(ConsistOf(
+				policy.Violation{Source: EnvoyValidateProtoMatcher.Package, Reason: "source is not allowed"},
+			))
+		})
+		It("can stamp provenance headers into vendored files", func() {
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: EnvoyValidateProtoMatcher}},
+				},
+			}
+			modules, err := mgr.gather(mgr.gatherOptions(cfg))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			Expect(mgr.stampProvenance(modules)).NotTo(HaveOccurred())
+
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+			stamped, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(stamped)).To(HavePrefix(fmt.Sprintf("// Vendored by anyvendor. DO NOT EDIT.\n"+
+				"// source: %s\n// version: %s\n// path: validate/validate.proto\n",
+				modules[0].module.Path, modules[0].module.Version)))
+			// stamping again replaces the header
+			Expect(mgr.stampProvenance(modules)).NotTo(HaveOccurred())
+			restamped, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restamped).To(Equal(stamped))
+
+			// the header is not part of a generated patch
+			var diff strings.Builder
+			Expect(mgr.generatePatch(context.Background(), cfg, []string{vendored}, &diff)).NotTo(HaveOccurred())
+			Expect(diff.String()).To(BeEmpty())
+		})
+		It("can write metadata to the vendored root of every module", func() {
+			modules, err := mgr.gather(goModOptions{
+				MatchOptions:  []*anyvendor.GoModImport{EnvoyValidateProtoMatcher},
+				LocalMatchers: []string{"anyvendor/**/*.proto"},
+				SkipLicenses:  true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			Expect(mgr.writeMetadata(modules)).NotTo(HaveOccurred())
+
+			metadata, err := provenance.ReadMetadata(mgr.fs, mgr.vendorRoot(modules[1]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Type).To(Equal(provenance.SourceTypeGoMod))
+			Expect(metadata.Source).To(Equal(EnvoyValidateProtoMatcher.Package))
+			Expect(metadata.Version).To(Equal(modules[1].module.Version))
+			Expect(metadata.Patterns).To(Equal(EnvoyValidateProtoMatcher.Patterns))
+			Expect(metadata.Files).To(HaveLen(1))
+			Expect(metadata.Files[0].Path).To(Equal("validate/validate.proto"))
+			Expect(metadata.Files[0].Vendored).To(Equal(EnvoyValidateProtoMatcher.Package + "/validate/validate.proto"))
+			hash, err := provenance.HashFile(mgr.fs, filepath.Join(modules[1].module.Dir, "validate", "validate.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Files[0].SHA256).To(Equal(hash))
+
+			metadata, err = provenance.ReadMetadata(mgr.fs, mgr.vendorRoot(modules[0]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Type).To(Equal(provenance.SourceTypeLocal))
+		})
+		It("can plan and apply changes to the vendor dir", func() {
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: EnvoyValidateProtoMatcher}},
+				},
+				SkipLicenses: true,
+			}
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+
+			plan, err := mgr.plan(context.Background(), cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Files).To(HaveLen(1))
+			Expect(plan.Files[0].Destination).To(Equal(vendored))
+			Expect(plan.Files[0].Action).To(Equal(ActionCreate))
+			var summary strings.Builder
+			Expect(plan.Print(&summary)).NotTo(HaveOccurred())
+			Expect(summary.String()).To(Equal(fmt.Sprintf("+ vendor_any/%s/validate/validate.proto (%s)\n"+
+				"1 to create, 0 to update, 0 unchanged, 0 to delete\n",
+				EnvoyValidateProtoMatcher.Package, EnvoyValidateProtoMatcher.Package)))
+			// nothing has been vendored yet
+			_, err = os.Stat(vendored)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			Expect(mgr.apply(context.Background(), plan)).NotTo(HaveOccurred())
+			plan, err = mgr.plan(context.Background(), cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Changed()).To(BeFalse())
+			Expect(plan.Files[0].Action).To(Equal(ActionUnchanged))
+
+			// only files listed in the metadata file were vendored by the config, and may be stale
+			stale := filepath.Join(filepath.Dir(vendored), "removed.proto")
+			unlisted := filepath.Join(filepath.Dir(vendored), "unlisted.proto")
+			listStale := func() {
+				root := mgr.vendorRoot(plan.modules[0])
+				metadata, err := provenance.ReadMetadata(mgr.fs, root)
+				Expect(err).NotTo(HaveOccurred())
+				metadata.Files = append(metadata.Files, provenance.File{
+					Path:     "validate/removed.proto",
+					Vendored: EnvoyValidateProtoMatcher.Package + "/validate/removed.proto",
+				})
+				Expect(provenance.WriteMetadata(mgr.fs, root, metadata)).NotTo(HaveOccurred())
+			}
+			for _, path := range []string{stale, unlisted} {
+				Expect(os.WriteFile(path, []byte("syntax = \"proto3\";\n"), 0644)).NotTo(HaveOccurred())
+			}
+			listStale()
+			// ensure never deletes files
+			Expect(mgr.Ensure(context.Background(), cfg)).NotTo(HaveOccurred())
+			_, err = os.Stat(stale)
+			Expect(err).NotTo(HaveOccurred())
+
+			listStale()
+			Expect(os.WriteFile(vendored, []byte("syntax = \"proto3\";\n"), 0644)).NotTo(HaveOccurred())
+			plan, err = mgr.plan(context.Background(), cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Files).To(ConsistOf(
+				PlannedFile{Module: EnvoyValidateProtoMatcher.Package, Destination: stale, Action: ActionDelete},
+				PlannedFile{
+					Module:      EnvoyValidateProtoMatcher.Package,
+					Source:      plan.Files[1].Source,
+					Destination: vendored,
+					Action:      ActionUpdate,
+				},
+			))
+
+			Expect(mgr.apply(context.Background(), plan)).NotTo(HaveOccurred())
+			_, err = os.Stat(stale)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			_, err = os.Stat(unlisted)
+			Expect(err).NotTo(HaveOccurred())
+			plan, err = mgr.plan(context.Background(), cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Changed()).To(BeFalse())
+		})
+		It("can diff the vendored files against a candidate version", func() {
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: EnvoyValidateProtoMatcher}},
+				},
+				SkipLicenses: true,
+			}
+			modules, err := mgr.gather(mgr.gatherOptions(cfg))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+
+			ctx := context.Background()
+			var diff strings.Builder
+			Expect(mgr.diffCandidate(ctx, cfg, EnvoyValidateProtoMatcher.Package, modules[0].module.Version, &diff)).
+				NotTo(HaveOccurred())
+			Expect(diff.String()).To(BeEmpty())
+
+			Expect(mgr.diffCandidate(ctx, cfg, EnvoyValidateProtoMatcher.Package, "v1.3.3", &diff)).NotTo(HaveOccurred())
+			Expect(diff.String()).To(HavePrefix("diff --git a/validate/validate.proto b/validate/validate.proto\n"))
+			Expect(diff.String()).To(ContainSubstring("\n--- a/validate/validate.proto\n+++ b/validate/validate.proto\n@@ "))
+
+			err = mgr.diffCandidate(ctx, cfg, "github.com/solo-io/not-vendored", "v1.0.0", &diff)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not vendored"))
+
+			// the candidate is patched and stamped just as the vendored files are
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+			original, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			edited := strings.Replace(string(original), "package validate;", "package validate;\n// patched", 1)
+			patchFile := filepath.Join(GinkgoT().TempDir(), "validate.patch")
+			Expect(os.WriteFile(patchFile, []byte(patch.Diff("validate/validate.proto", "validate/validate.proto",
+				original, []byte(edited))), 0644)).To(Succeed())
+			imp := *EnvoyValidateProtoMatcher
+			imp.Patches = []string{patchFile}
+			cfg.Imports = []*anyvendor.Import{{ImportType: &anyvendor.Import_GoMod{GoMod: &imp}}}
+			cfg.ProvenanceHeaders = true
+			Expect(mgr.Ensure(ctx, cfg)).To(Succeed())
+			diff.Reset()
+			Expect(mgr.diffCandidate(ctx, cfg, EnvoyValidateProtoMatcher.Package, modules[0].module.Version, &diff)).
+				To(Succeed())
+			Expect(diff.String()).To(BeEmpty())
+		})
+		It("can report outdated modules", func() {
+			report, err := mgr.outdated(&anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: EnvoyValidateProtoMatcher}},
+				},
+				Local: &anyvendor.Local{Patterns: []string{"anyvendor/**/*.proto"}},
+				// neither is enforced by a report: the module is not allowed, and the well known imports do not resolve
+				Settings: &anyvendor.FactorySettings{
+					Policy: &anyvendor.Policy{AllowedSources: []string{"github.com/solo-io/**"}},
+				},
+				ResolveImports: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// the local module is not reported
+			Expect(report).To(HaveLen(1))
+			Expect(report[0].Type).To(Equal(provenance.SourceTypeGoMod))
+			Expect(report[0].Name).To(Equal(EnvoyValidateProtoMatcher.Package))
+			Expect(report[0].Current).To(Equal("v0.6.1"))
+			Expect(report[0].Latest).NotTo(BeEmpty())
+			Expect(report[0].Outdated).To(Equal(report[0].Latest != report[0].Current))
+		})
+		It("rejects configs with unresolved includes", func() {
+			manager, err := NewManager(context.Background(), modPathString)
+			Expect(err).NotTo(HaveOccurred())
+			err = manager.Ensure(context.Background(), &anyvendor.Config{Includes: []string{"base.yaml"}})
+			Expect(eris.Is(err, UnresolvedIncludesError)).To(BeTrue())
+		})
+		It("can generate and apply patches to vendored files", func() {
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: EnvoyValidateProtoMatcher}},
+				},
+			}
+			modules, err := mgr.gather(mgr.gatherOptions(cfg))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+			original, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			edited := strings.Replace(string(original), "package validate;", "package validate;\n// patched", 1)
+			Expect(os.WriteFile(vendored, []byte(edited), 0644)).NotTo(HaveOccurred())
+
+			var diff strings.Builder
+			Expect(mgr.generatePatch(context.Background(), cfg, []string{vendored}, &diff)).NotTo(HaveOccurred())
+			Expect(diff.String()).To(ContainSubstring("--- a/validate/validate.proto"))
+			Expect(diff.String()).To(ContainSubstring("+// patched"))
+
+			patchFile := filepath.Join(GinkgoT().TempDir(), "validate.patch")
+			Expect(os.WriteFile(patchFile, []byte(diff.String()), 0644)).NotTo(HaveOccurred())
+			Expect(mgr.copy(modules)).NotTo(HaveOccurred())
+			imp := *EnvoyValidateProtoMatcher
+			imp.Patches = []string{patchFile}
+			Expect(mgr.applyPatches(modules, []*anyvendor.GoModImport{&imp})).NotTo(HaveOccurred())
+			patched, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(patched)).To(Equal(edited))
+
+			// the patched file has changed upstream, so the patch no longer applies
+			Expect(os.WriteFile(vendored, []byte("syntax = \"proto3\";\n"), 0644)).NotTo(HaveOccurred())
+			err = mgr.applyPatches(modules, []*anyvendor.GoModImport{&imp})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no longer applies"))
+		})
+		It("leaves the edits the config makes itself out of generated patches", func() {
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+			imp := *EnvoyValidateProtoMatcher
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{{ImportType: &anyvendor.Import_GoMod{GoMod: &imp}}},
+				Patch: &anyvendor.Patch{
+					ImportRewrites: []*anyvendor.ImportRewrite{{From: "google/protobuf/timestamp.proto", To: "gp/timestamp.proto"}},
+				},
+				SkipLicenses:      true,
+				ProvenanceHeaders: true,
+			}
+			ctx := context.Background()
+			plan, err := mgr.resolve(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.apply(ctx, plan)).To(Succeed())
+
+			vendored := filepath.Join(modPathString, anyvendor.DefaultDepDir, EnvoyValidateProtoMatcher.Package,
+				"validate", "validate.proto")
+			content, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(vendored, append(content, []byte("// patched\n")...), 0644)).NotTo(HaveOccurred())
+
+			var diff strings.Builder
+			Expect(mgr.generatePatch(ctx, cfg, []string{vendored}, &diff)).NotTo(HaveOccurred())
+			Expect(diff.String()).To(ContainSubstring("+// patched"))
+			Expect(diff.String()).NotTo(ContainSubstring("gp/timestamp.proto"))
+			Expect(diff.String()).NotTo(ContainSubstring("Vendored by anyvendor"))
+
+			// the patch applies to the files as they are copied, before the rest of the config
+			patchFile := filepath.Join(GinkgoT().TempDir(), "validate.patch")
+			Expect(os.WriteFile(patchFile, []byte(diff.String()), 0644)).NotTo(HaveOccurred())
+			imp.Patches = []string{patchFile}
+			plan, err = mgr.resolve(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.apply(ctx, plan)).To(Succeed())
+			patched, err := os.ReadFile(vendored)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(patched)).To(ContainSubstring(`import "gp/timestamp.proto";`))
+			Expect(string(patched)).To(HaveSuffix("// patched\n"))
+			Expect(os.RemoveAll(filepath.Join(modPathString, anyvendor.DefaultDepDir))).NotTo(HaveOccurred())
+		})
+	})
+	Context("major versions", func() {
+		It("only shades the major version of the module which is imported", func() {
+			workingDirectory := GinkgoT().TempDir()
+			newModule := func(path, pkg string) *modutils.Module {
+				dir := GinkgoT().TempDir()
+				Expect(os.WriteFile(filepath.Join(dir, "api.proto"),
+					[]byte(fmt.Sprintf("syntax = \"proto3\";\npackage %s;\nmessage Api {}\n", pkg)), 0644)).To(Succeed())
+				return &modutils.Module{Path: path, Version: "v1.0.0", Dir: dir}
+			}
+			v1 := newModule("github.com/solo-io/api", "solo.api")
+			v2 := newModule("github.com/solo-io/api/v2", "solo.api.v2")
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+			imports := []*anyvendor.GoModImport{
+				{
+					Package:  "github.com/solo-io/api",
+					Patterns: []string{"*.proto"},
+					Shade:    &anyvendor.Shade{PackagePrefix: "shaded", PathPrefix: "shaded"},
+				},
+				{Package: "github.com/solo-io/api/v2", Patterns: []string{"*.proto"}},
+			}
+			var modules []*moduleWithImports
+			for _, module := range []*modutils.Module{v1, v2} {
+				mod, err := mgr.handleSingleModule(module, imports)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mod.vendorList).To(ConsistOf(filepath.Join(module.Dir, "api.proto")))
+				modules = append(modules, mod)
+			}
+			Expect(mgr.copy(modules)).To(Succeed())
+			Expect(mgr.shadeModules(context.Background(), modules, imports)).To(Succeed())
+
+			vendorDir := filepath.Join(workingDirectory, anyvendor.DefaultDepDir)
+			content, err := os.ReadFile(filepath.Join(vendorDir, "shaded", "api.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("package shaded.solo.api;"))
+			Expect(filepath.Join(vendorDir, "github.com", "solo-io", "api", "api.proto")).NotTo(BeAnExistingFile())
+			content, err = os.ReadFile(filepath.Join(vendorDir, "github.com", "solo-io", "api", "v2", "api.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("package solo.api.v2;"))
+			Expect(modules[1].renamed).To(BeEmpty())
+		})
+		It("fails when a shaded extension keeps the number of an extension which is vendored too", func() {
+			workingDirectory := GinkgoT().TempDir()
+			newModule := func(path, pkg string) *modutils.Module {
+				dir := GinkgoT().TempDir()
+				Expect(os.WriteFile(filepath.Join(dir, "options.proto"), []byte(fmt.Sprintf(`syntax = "proto3";
+package %s;
+import "google/protobuf/descriptor.proto";
+extend google.protobuf.FieldOptions {
+  string owner = 50000;
+}
+`, pkg)), 0644)).To(Succeed())
+				return &modutils.Module{Path: path, Version: "v1.0.0", Dir: dir}
+			}
+			v1 := newModule("github.com/solo-io/api", "solo.api")
+			v2 := newModule("github.com/solo-io/api/v2", "solo.api.v2")
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+			imports := []*anyvendor.GoModImport{
+				{
+					Package:  "github.com/solo-io/api",
+					Patterns: []string{"*.proto"},
+					Shade:    &anyvendor.Shade{PackagePrefix: "shaded", PathPrefix: "shaded"},
+				},
+				{Package: "github.com/solo-io/api/v2", Patterns: []string{"*.proto"}},
+			}
+			var modules []*moduleWithImports
+			for _, module := range []*modutils.Module{v1, v2} {
+				mod, err := mgr.handleSingleModule(module, imports)
+				Expect(err).NotTo(HaveOccurred())
+				modules = append(modules, mod)
+			}
+			Expect(mgr.copy(modules)).To(Succeed())
+			err := mgr.shadeModules(context.Background(), modules, imports)
+			var conflicts *protoutils.ExtensionConflictsError
+			Expect(errors.As(err, &conflicts)).To(BeTrue())
+			Expect(conflicts.Conflicts).To(HaveLen(1))
+			Expect(err.Error()).To(ContainSubstring("shaded/options.proto: shaded.solo.api.owner extends " +
+				"google.protobuf.FieldOptions with number 50000, which github.com/solo-io/api/v2/options.proto already uses for owner"))
+
+			// the shaded extensions are fine on their own
+			Expect(os.RemoveAll(filepath.Join(workingDirectory, anyvendor.DefaultDepDir))).To(Succeed())
+			mod, err := mgr.handleSingleModule(v1, imports)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy([]*moduleWithImports{mod})).To(Succeed())
+			Expect(mgr.shadeModules(context.Background(), []*moduleWithImports{mod}, imports)).To(Succeed())
+		})
+	})
+
+	Context("plans", func() {
+		It("plans the vendored files once they have been patched, shaded and stamped", func() {
+			workingDirectory := GinkgoT().TempDir()
+			newModule := func(path string, files map[string]string) *modutils.Module {
+				dir := GinkgoT().TempDir()
+				for name, content := range files {
+					Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)).To(Succeed())
+				}
+				return &modutils.Module{Path: path, Version: "v1.0.0", Dir: dir}
+			}
+			shadedModule := newModule("github.com/solo-io/shaded", map[string]string{
+				"api.proto": "syntax = \"proto3\";\npackage solo.api;\nmessage Api {}\n",
+			})
+			patchedModule := newModule("github.com/solo-io/patched", map[string]string{
+				"patched.proto": "syntax = \"proto3\";\npackage solo.patched;\n",
+				"deleted.proto": "syntax = \"proto3\";\npackage solo.deleted;\n",
+			})
+			patchFile := filepath.Join(GinkgoT().TempDir(), "module.patch")
+			Expect(os.WriteFile(patchFile, []byte("--- a/patched.proto\n+++ b/patched.proto\n"+
+				"@@ -1,2 +1,3 @@\n syntax = \"proto3\";\n package solo.patched;\n+// patched\n"+
+				"--- a/deleted.proto\n+++ /dev/null\n"+
+				"@@ -1,2 +0,0 @@\n-syntax = \"proto3\";\n-package solo.deleted;\n"), 0644)).To(Succeed())
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+			cfg := &anyvendor.Config{
+				Imports: []*anyvendor.Import{
+					{ImportType: &anyvendor.Import_GoMod{GoMod: &anyvendor.GoModImport{
+						Package:  shadedModule.Path,
+						Patterns: []string{"*.proto"},
+						Shade:    &anyvendor.Shade{PackagePrefix: "shaded", PathPrefix: "shaded"},
+					}}},
+					{ImportType: &anyvendor.Import_GoMod{GoMod: &anyvendor.GoModImport{
+						Package:  patchedModule.Path,
+						Patterns: []string{"*.proto"},
+						Patches:  []string{patchFile},
+					}}},
+				},
+				SkipLicenses:      true,
+				ProvenanceHeaders: true,
+			}
+			plan := &Plan{workingDirectory: workingDirectory, opts: cfg, gatherOpts: mgr.gatherOptions(cfg)}
+			for _, module := range []*modutils.Module{shadedModule, patchedModule} {
+				mod, err := mgr.handleSingleModule(module, plan.gatherOpts.MatchOptions)
+				Expect(err).NotTo(HaveOccurred())
+				plan.modules = append(plan.modules, mod)
+			}
+
+			vendorDir := filepath.Join(workingDirectory, anyvendor.DefaultDepDir)
+			Expect(mgr.planFiles(context.Background(), plan)).To(Succeed())
+			Expect(plan.Files).To(Equal([]PlannedFile{
+				{
+					Module:      patchedModule.Path,
+					Source:      filepath.Join(patchedModule.Dir, "patched.proto"),
+					Destination: filepath.Join(vendorDir, patchedModule.Path, "patched.proto"),
+					Action:      ActionCreate,
+				},
+				{
+					Module:      shadedModule.Path,
+					Source:      filepath.Join(shadedModule.Dir, "api.proto"),
+					Destination: filepath.Join(vendorDir, "shaded", "api.proto"),
+					Action:      ActionCreate,
+				},
+			}))
+			// planning runs in memory
+			Expect(vendorDir).NotTo(BeADirectory())
+
+			Expect(mgr.apply(context.Background(), plan)).To(Succeed())
+			plan.Files = nil
+			Expect(mgr.planFiles(context.Background(), plan)).To(Succeed())
+			Expect(plan.Changed()).To(BeFalse())
+			Expect(plan.Files).To(HaveLen(2))
+		})
+		It("plans and rewrites the imports of the local protos in place when asked", func() {
+			workingDirectory := GinkgoT().TempDir()
+			source := filepath.Join(workingDirectory, "api", "api.proto")
+			Expect(os.MkdirAll(filepath.Dir(source), 0755)).To(Succeed())
+			Expect(os.WriteFile(source, []byte("syntax = \"proto3\";\nimport \"validate/validate.proto\";\n"), 0644)).To(Succeed())
+			local := &modutils.Module{Path: "github.com/solo-io/local", Dir: workingDirectory, Main: true}
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+			cfg := &anyvendor.Config{
+				Local: &anyvendor.Local{Patterns: []string{"api/*.proto"}},
+				Patch: &anyvendor.Patch{
+					ImportRewrites: []*anyvendor.ImportRewrite{{From: "validate/", To: "pgv/"}},
+					RewriteLocal:   true,
+				},
+			}
+			plan := &Plan{workingDirectory: workingDirectory, opts: cfg, gatherOpts: mgr.gatherOptions(cfg)}
+			mod, err := mgr.handleSingleModule(local, []*anyvendor.GoModImport{{
+				Package:  local.Path,
+				Patterns: plan.gatherOpts.LocalMatchers,
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			plan.modules = []*moduleWithImports{mod}
+
+			vendored := filepath.Join(workingDirectory, anyvendor.DefaultDepDir, local.Path, "api", "api.proto")
+			Expect(mgr.planFiles(context.Background(), plan)).To(Succeed())
+			Expect(plan.Files).To(Equal([]PlannedFile{
+				{Module: local.Path, Source: source, Destination: source, Action: ActionUpdate},
+				{Module: local.Path, Source: source, Destination: vendored, Action: ActionCreate},
+			}))
+
+			Expect(mgr.apply(context.Background(), plan)).To(Succeed())
+			for _, path := range []string{source, vendored} {
+				content, err := os.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring(`import "pgv/validate.proto";`))
+			}
+			plan.Files = nil
+			Expect(mgr.planFiles(context.Background(), plan)).To(Succeed())
+			Expect(plan.Changed()).To(BeFalse())
+		})
+	})
+
+	Context("stripping proto options", func() {
+		It("reads the packages of imports from the modules", func() {
+			workingDirectory := GinkgoT().TempDir()
+			writeModule := func(path string, files map[string]string) *modutils.Module {
+				dir := GinkgoT().TempDir()
+				for name, content := range files {
+					Expect(os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)).To(Succeed())
+				}
+				return &modutils.Module{Path: path, Version: "v1.0.0", Dir: dir}
+			}
+			pgv := writeModule("github.com/envoyproxy/protoc-gen-validate", map[string]string{
+				"validate/validate.proto": "syntax = \"proto2\";\npackage validate;\n",
+			})
+			api := writeModule("github.com/solo-io/api", map[string]string{
+				"api/api.proto": `syntax = "proto3";
+package api;
+import "validate/validate.proto";
+import "github.com/solo-io/api/api/types.proto";
+message Api {
+  string name = 1 [(validate.rules).string.min_len = 1];
+}
+`,
+				"api/types.proto": "syntax = \"proto3\";\npackage validate.types;\n",
+			})
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+
+			resolver := mgr.importResolver([]*modutils.Module{api, pgv})
+			content, ok := resolver("github.com/solo-io/api/api/types.proto")
+			Expect(ok).To(BeTrue())
+			Expect(string(content)).To(ContainSubstring("package validate.types;"))
+			_, ok = resolver("validate/validate.proto")
+			Expect(ok).To(BeTrue())
+			_, ok = resolver("missing/missing.proto")
+			Expect(ok).To(BeFalse())
+
+			imports := []*anyvendor.GoModImport{{
+				Package:  api.Path,
+				Patterns: []string{"api/api.proto"},
+				Transforms: []*anyvendor.Transform{{
+					TransformType: &anyvendor.Transform_StripProtoOptions{
+						StripProtoOptions: &anyvendor.StripProtoOptions{Packages: []string{"validate"}},
+					},
+				}},
+			}}
+			mod, err := mgr.handleSingleModule(api, imports)
+			Expect(err).NotTo(HaveOccurred())
+			mod.transformer, err = mgr.moduleTransformer(api, imports, resolver)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.copy([]*moduleWithImports{mod})).To(Succeed())
+			b, err := os.ReadFile(filepath.Join(workingDirectory, anyvendor.DefaultDepDir, api.Path, "api", "api.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			// types.proto declares a package nested in validate, not validate itself, so it is kept
+			Expect(string(b)).To(Equal(`syntax = "proto3";
+package api;
+import "github.com/solo-io/api/api/types.proto";
+message Api {
+  string name = 1;
+}
+`))
+		})
+	})
+
+	Context("descriptor sets", func() {
+		It("names files the way they are imported, and searches the extra roots", func() {
+			workingDirectory := GinkgoT().TempDir()
+			writeFile := func(path, content string) {
+				Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+				Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+			}
+			api := &modutils.Module{Path: "github.com/solo-io/api", Version: "v1.0.0", Dir: GinkgoT().TempDir()}
+			writeFile(filepath.Join(api.Dir, "api", "types.proto"), "syntax = \"proto3\";\npackage api;\nmessage Type {}\n")
+			app := &modutils.Module{Path: "github.com/solo-io/app", Version: "v1.0.0", Dir: GinkgoT().TempDir()}
+			writeFile(filepath.Join(app.Dir, "app.proto"), `syntax = "proto3";
+package app;
+import "github.com/solo-io/api/api/types.proto";
+import "extra/extra.proto";
+message App {
+  api.Type type = 1;
+  extra.Extra extra = 2;
+}
+`)
+			writeFile(filepath.Join(workingDirectory, "protos", "extra", "extra.proto"),
+				"syntax = \"proto3\";\npackage extra;\nmessage Extra {}\n")
+
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+			imports := []*anyvendor.GoModImport{
+				{Package: api.Path, Patterns: []string{"**/*.proto"}},
+				{Package: app.Path, Patterns: []string{"*.proto"}},
+			}
+			var modules []*moduleWithImports
+			for _, module := range []*modutils.Module{api, app} {
+				mod, err := mgr.handleSingleModule(module, imports)
+				Expect(err).NotTo(HaveOccurred())
+				modules = append(modules, mod)
+			}
+			Expect(mgr.copy(modules)).To(Succeed())
+
+			output := filepath.Join(workingDirectory, "descriptors.binpb")
+			Expect(mgr.writeDescriptorSet(context.Background(), modules, &anyvendor.DescriptorSet{Path: output}, nil)).
+				NotTo(Succeed())
+			Expect(mgr.writeDescriptorSet(context.Background(), modules, &anyvendor.DescriptorSet{Path: output},
+				[]string{"protos"})).To(Succeed())
+			b, err := os.ReadFile(output)
+			Expect(err).NotTo(HaveOccurred())
+			set := &descriptorpb.FileDescriptorSet{}
+			Expect(proto.Unmarshal(b, set)).To(Succeed())
+			var names []string
+			for _, file := range set.GetFile() {
+				names = append(names, file.GetName())
+			}
+			Expect(names).To(Equal([]string{"github.com/solo-io/api/api/types.proto", "app.proto"}))
+		})
+	})
+
+	Context("go_package mappings", func() {
+		It("fails when module relative paths of different modules map to different packages", func() {
+			workingDirectory := GinkgoT().TempDir()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: afero.NewOsFs()}
+			var modules []*moduleWithImports
+			for _, path := range []string{"github.com/solo-io/foo", "github.com/solo-io/bar"} {
+				dir := filepath.Join(GinkgoT().TempDir(), path)
+				modules = append(modules, &moduleWithImports{
+					module:     &modutils.Module{Path: path, Dir: dir},
+					vendorList: []string{filepath.Join(dir, "api", "api.proto")},
+				})
+			}
+			output := &anyvendor.GoPackageMapping{Path: "mapping.txt", ModuleRelative: true}
+
+			Expect(mgr.writeGoPackageMapping(modules, protoutils.GoPackageRules{
+				{Pattern: "**/*.proto", GoPackage: "github.com/solo-io/api"},
+			}, output)).To(Succeed())
+			content, err := os.ReadFile(filepath.Join(workingDirectory, "mapping.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("api/api.proto=github.com/solo-io/api"))
+
+			err = mgr.writeGoPackageMapping(modules, protoutils.GoPackageRules{
+				{Pattern: "**/*.proto", GoPackage: "{module}/api"},
+			}, output)
+			Expect(eris.Is(err, GoPackageMappingCollisionError)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("github.com/solo-io/foo/api/api.proto"))
+			Expect(err.Error()).To(ContainSubstring("github.com/solo-io/bar/api/api.proto"))
+		})
+	})
+
+	Context("patch files", func() {
+		var root string
+		writePatch := func(content string) string {
+			patchFile := filepath.Join(GinkgoT().TempDir(), "fix.patch")
+			Expect(os.WriteFile(patchFile, []byte(content), 0644)).To(Succeed())
+			return patchFile
+		}
+		BeforeEach(func() {
+			root = filepath.Join(GinkgoT().TempDir(), "vendor_any", "github.com", "foo", "bar")
+			Expect(os.MkdirAll(root, 0755)).To(Succeed())
+		})
+
+		It("keeps the mode of patched files", func() {
+			script := filepath.Join(root, "gen.sh")
+			Expect(os.WriteFile(script, []byte("echo a\n"), 0755)).To(Succeed())
+			Expect(applyPatchFile(afero.NewOsFs(), writePatch("--- a/gen.sh\n+++ b/gen.sh\n@@ -1 +1 @@\n-echo a\n+echo b\n"), root, nil)).
+				To(Succeed())
+			content, err := os.ReadFile(script)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("echo b\n"))
+			info, err := os.Stat(script)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		})
+
+		It("rejects patches of files outside the vendored root", func() {
+			for _, name := range []string{"../../escaped.proto", "/tmp/escaped.proto"} {
+				patchFile := writePatch(fmt.Sprintf("--- /dev/null\n+++ %s\n@@ -0,0 +1 @@\n+escaped\n", name))
+				err := applyPatchFile(afero.NewOsFs(), patchFile, root, nil)
+				Expect(err).To(HaveOccurred())
+				Expect(eris.Is(err, PatchOutsideVendorRootError)).To(BeTrue(), err.Error())
+			}
+			Expect(filepath.Join(root, "..", "..", "escaped.proto")).NotTo(BeAnExistingFile())
+		})
+
+		It("matches modules to the package of an import", func() {
+			Expect(importsModule("github.com/solo-io/api", "github.com/solo-io/api")).To(BeTrue())
+			Expect(importsModule("github.com/solo-io/api", "github.com/solo-io/api/nested")).To(BeTrue())
+			Expect(importsModule("", "github.com/solo-io/api")).To(BeTrue())
+			Expect(importsModule("github.com/solo-io/api", "github.com/solo-io/api/v2")).To(BeFalse())
+			Expect(importsModule("github.com/solo-io/api", "github.com/solo-io/api-gateway")).To(BeFalse())
+			Expect(importsModule("github.com/solo-io/api/v2", "github.com/solo-io/api")).To(BeFalse())
+		})
+	})
+	Context("license files", func() {
+		It("vendors license files verbatim", func() {
+			workingDirectory := GinkgoT().TempDir()
+			module := &modutils.Module{Path: "github.com/solo-io/api", Version: "v1.0.0", Dir: GinkgoT().TempDir()}
+			Expect(os.WriteFile(filepath.Join(module.Dir, "LICENSE"), []byte("Copyright solo-io\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(module.Dir, "api.proto"),
+				[]byte("syntax = \"proto3\";\n// Copyright solo-io\npackage api;\n"), 0644)).To(Succeed())
+			patchFile := filepath.Join(workingDirectory, "license.patch")
+			Expect(os.WriteFile(patchFile, []byte("--- a/LICENSE\n+++ b/LICENSE\n@@ -1 +1 @@\n-Copyright solo-io\n+Copyright me\n"), 0644)).
+				To(Succeed())
+			fs := afero.NewOsFs()
+			mgr = &goModFactory{WorkingDirectory: workingDirectory, fs: fs, fileCopier: NewCopier(fs, nil)}
+			imports := []*anyvendor.GoModImport{{
+				Package:  module.Path,
+				Patterns: []string{"*.proto"},
+				Transforms: []*anyvendor.Transform{{
+					TransformType: &anyvendor.Transform_RegexReplace{
+						RegexReplace: &anyvendor.RegexReplace{Pattern: "solo-io", Replacement: "someone"},
+					},
+				}},
+				Patches: []string{patchFile},
+			}}
+			mod, err := mgr.handleSingleModule(module, imports)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.addLicenseFiles(mod)).To(Succeed())
+			mod.transformer, err = mgr.moduleTransformer(module, imports, nil)
+			Expect(err).NotTo(HaveOccurred())
+			modules := []*moduleWithImports{mod}
+			Expect(mgr.copy(modules)).To(Succeed())
+			Expect(mgr.stampProvenance(modules)).To(Succeed())
+
+			root := mgr.vendorRoot(mod)
+			content, err := os.ReadFile(filepath.Join(root, "LICENSE"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("Copyright solo-io\n"))
+			content, err = os.ReadFile(filepath.Join(root, "api.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("// Copyright someone\n"))
+
+			err = mgr.applyPatches(modules, imports)
+			Expect(eris.Is(err, PatchLicenseFileError)).To(BeTrue())
+		})
 	})
 })
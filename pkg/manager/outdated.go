@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/modutils"
+	"github.com/solo-io/anyvendor/pkg/outdated"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+)
+
+/*
+report the current and newest version of every module files are vendored from. Nothing is vendored, so the policy is
+not enforced, and the imports of the vendored files are not resolved.
+*/
+func (m *goModFactory) outdated(opts *anyvendor.Config) (outdated.Report, error) {
+	gatherOpts := m.gatherOptions(opts)
+	gatherOpts.ResolveImports, gatherOpts.SkipPolicy = false, true
+	mods, err := m.gather(gatherOpts)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, mod := range mods {
+		if !mod.module.Main {
+			paths = append(paths, mod.module.Path)
+		}
+	}
+	updates, err := modutils.GetModuleUpdatesIn(m.WorkingDirectory, paths)
+	if err != nil {
+		return nil, err
+	}
+	var report outdated.Report
+	for _, module := range updates {
+		source := outdated.Source{
+			Type:    provenance.SourceTypeGoMod,
+			Name:    module.Path,
+			Current: module.Version,
+			Latest:  module.Version,
+		}
+		if module.Update != nil {
+			source.Latest = module.Update.Version
+			source.Outdated = true
+		}
+		report = append(report, source)
+	}
+	return report, nil
+}
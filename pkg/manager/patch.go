@@ -0,0 +1,510 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/patch"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"github.com/spf13/afero"
+)
+
+var PatchOutsideVendorRootError = eris.New("patch targets a file outside the vendored root of the module")
+
+var PatchLicenseFileError = eris.New("patch modifies a license file, license files are vendored verbatim")
+
+var GoPackageMappingCollisionError = eris.New("module relative go_package mapping maps the same path to different packages")
+
+// convert the go_package rules from the config into their pkg/proto equivalent
+func goPackageRules(patch *anyvendor.Patch) protoutils.GoPackageRules {
+	var rules protoutils.GoPackageRules
+	for _, rule := range patch.GetGoPackageRules() {
+		rules = append(rules, protoutils.GoPackageRule{
+			Pattern:   rule.GetPattern(),
+			GoPackage: rule.GetGoPackage(),
+		})
+	}
+	return rules
+}
+
+// a vendored proto file matched by one of the go_package rules
+type goPackageMatch struct {
+	// path of the vendored file
+	localFile string
+	// path relative to the vendor dir, and to the module root
+	vendorPath, modulePath string
+	goPackage              string
+}
+
+// returns every vendored proto file which is matched by one of the rules
+func (m *goModFactory) matchGoPackageRules(modules []*moduleWithImports, rules protoutils.GoPackageRules) ([]goPackageMatch, error) {
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	var matches []goPackageMatch
+	for _, mod := range modules {
+		for _, vendorFile := range mod.vendorList {
+			localFile := m.destination(mod, vendorFile)
+			if filepath.Ext(localFile) != ".proto" {
+				continue
+			}
+			vendorPath, err := filepath.Rel(vendorDir, localFile)
+			if err != nil {
+				return nil, err
+			}
+			goPackage, err := rules.GoPackage(vendorPath, mod.module.Path)
+			if err != nil {
+				return nil, err
+			}
+			if goPackage == "" {
+				continue
+			}
+			modulePath, err := filepath.Rel(filepath.Join(vendorDir, mod.module.Path), localFile)
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, goPackageMatch{
+				localFile:  localFile,
+				vendorPath: filepath.ToSlash(vendorPath),
+				modulePath: filepath.ToSlash(modulePath),
+				goPackage:  goPackage,
+			})
+		}
+	}
+	return matches, nil
+}
+
+// set the go_package option of every vendored proto file matched by one of the rules
+func (m *goModFactory) applyGoPackageRules(modules []*moduleWithImports, rules protoutils.GoPackageRules) error {
+	matches, err := m.matchGoPackageRules(modules, rules)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		err := m.editFile(match.localFile, func(content []byte) ([]byte, error) {
+			return protoutils.ApplyFileOptions(match.localFile, content,
+				protoutils.SetOption(protoutils.GoPackageOption, match.goPackage))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replace the contents of the file with the result of edit, keeping its mode
+func (m *goModFactory) editFile(path string, edit func(content []byte) ([]byte, error)) error {
+	content, err := afero.ReadFile(m.fs, path)
+	if err != nil {
+		return err
+	}
+	edited, err := edit(content)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, edited) {
+		return nil
+	}
+	fileInfo, err := m.fs.Stat(path)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(m.fs, path, edited, fileInfo.Mode())
+}
+
+// write the go_package of every vendored proto file matched by one of the rules to the mapping files
+func (m *goModFactory) writeGoPackageMapping(
+	modules []*moduleWithImports,
+	rules protoutils.GoPackageRules,
+	output *anyvendor.GoPackageMapping,
+) error {
+	matches, err := m.matchGoPackageRules(modules, rules)
+	if err != nil {
+		return err
+	}
+	mapping := protoutils.GoPackageMapping{}
+	// the vendored file each module relative path was mapped from
+	mappedFrom := map[string]string{}
+	for _, match := range matches {
+		if !output.GetModuleRelative() {
+			mapping[match.vendorPath] = match.goPackage
+			continue
+		}
+		if goPackage, ok := mapping[match.modulePath]; ok && goPackage != match.goPackage {
+			return eris.Wrapf(GoPackageMappingCollisionError, "%s is mapped to %s by %s, and to %s by %s",
+				match.modulePath, goPackage, mappedFrom[match.modulePath], match.goPackage, match.vendorPath)
+		}
+		mapping[match.modulePath] = match.goPackage
+		mappedFrom[match.modulePath] = match.vendorPath
+	}
+	if err := m.writeOutputFile(output.GetPath(), mapping.WriteMFlags); err != nil {
+		return err
+	}
+	if output.GetBufPath() != "" {
+		return m.writeOutputFile(output.GetBufPath(), mapping.WriteBufOverrides)
+	}
+	return nil
+}
+
+// create the file at path, relative to the working directory, with the contents written by write
+func (m *goModFactory) writeOutputFile(path string, write func(w io.Writer) error) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.WorkingDirectory, path)
+	}
+	if err := m.fs.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := m.fs.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// build the import mapping from the import rewrites in the config
+func importMapping(patch *anyvendor.Patch) protoutils.ImportMapping {
+	if len(patch.GetImportRewrites()) == 0 {
+		return nil
+	}
+	mapping := protoutils.ImportMapping{}
+	for _, rewrite := range patch.GetImportRewrites() {
+		mapping[rewrite.GetFrom()] = rewrite.GetTo()
+	}
+	return mapping
+}
+
+// rewrite the imports of every vendored proto file
+func (m *goModFactory) rewriteImports(modules []*moduleWithImports, mapping protoutils.ImportMapping) error {
+	for _, mod := range modules {
+		for _, vendorFile := range mod.vendorList {
+			localFile := m.destination(mod, vendorFile)
+			if filepath.Ext(localFile) != ".proto" {
+				continue
+			}
+			err := m.editFile(localFile, func(content []byte) ([]byte, error) {
+				return mapping.RewriteImports(localFile, content)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rewrite the imports of the source proto files of the local module, which are vendored by the modules, in place
+func (m *goModFactory) rewriteLocalImports(modules []*moduleWithImports, mapping protoutils.ImportMapping) error {
+	for _, mod := range modules {
+		if !mod.module.Main {
+			continue
+		}
+		for _, vendorFile := range mod.vendorList {
+			if filepath.Ext(vendorFile) != ".proto" {
+				continue
+			}
+			err := m.editFile(vendorFile, func(content []byte) ([]byte, error) {
+				return mapping.RewriteImports(vendorFile, content)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// shade the proto files vendored from the modules of every import with shading enabled
+func (m *goModFactory) shadeModules(ctx context.Context, modules []*moduleWithImports, imports []*anyvendor.GoModImport) error {
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	var (
+		extensions  []protoutils.Extension
+		shadedFiles = map[string]bool{}
+	)
+	for _, imp := range imports {
+		if imp.GetShade() == nil {
+			continue
+		}
+		for _, mod := range modules {
+			if mod.module.Main || !importsModule(imp.GetPackage(), mod.module.Path) {
+				continue
+			}
+			root := m.vendorRoot(mod)
+			var (
+				files       []string
+				vendorFiles = map[string]string{}
+			)
+			for _, vendorFile := range mod.vendorList {
+				localFile := m.destination(mod, vendorFile)
+				if filepath.Ext(localFile) != ".proto" {
+					continue
+				}
+				importPath := filepath.ToSlash(strings.TrimPrefix(localFile, root+string(filepath.Separator)))
+				files = append(files, importPath)
+				vendorFiles[importPath] = vendorFile
+			}
+			includeRoots := []string{vendorDir}
+			for _, other := range modules {
+				if other != mod {
+					includeRoots = append(includeRoots, m.vendorRoot(other))
+				}
+			}
+			shader := protoutils.Shader{
+				PackagePrefix: imp.GetShade().GetPackagePrefix(),
+				PathPrefix:    imp.GetShade().GetPathPrefix(),
+				IncludeRoots:  includeRoots,
+				Accessor: func(path string) (io.ReadCloser, error) {
+					return m.fs.Open(path)
+				},
+			}
+			shaded, err := shader.Shade(ctx, root, files)
+			if err != nil {
+				return err
+			}
+			for _, file := range shaded {
+				vendorFile := vendorFiles[file.OriginalPath]
+				shadedFile := filepath.Join(vendorDir, filepath.FromSlash(file.Path))
+				redact.Logf("shading %v -> %v", m.destination(mod, vendorFile), shadedFile)
+				if err := m.fs.MkdirAll(filepath.Dir(shadedFile), os.ModePerm); err != nil {
+					return err
+				}
+				if err := afero.WriteFile(m.fs, shadedFile, file.Content, 0644); err != nil {
+					return err
+				}
+				if err := m.fs.Remove(m.destination(mod, vendorFile)); err != nil {
+					return err
+				}
+				if mod.renamed == nil {
+					mod.renamed = map[string]string{}
+				}
+				mod.renamed[vendorFile] = shadedFile
+				shadedFiles[shadedFile] = true
+				extensions = append(extensions, file.Extensions...)
+			}
+		}
+	}
+	return m.checkShadedExtensions(modules, extensions, shadedFiles)
+}
+
+// fail if the extensions kept by shaded files conflict with the extensions of the vendored files which are not shaded
+func (m *goModFactory) checkShadedExtensions(
+	modules []*moduleWithImports,
+	extensions []protoutils.Extension,
+	shadedFiles map[string]bool,
+) error {
+	if len(extensions) == 0 {
+		return nil
+	}
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	var conflicts []protoutils.ExtensionConflict
+	for _, mod := range modules {
+		for _, vendorFile := range mod.vendorList {
+			localFile := m.destination(mod, vendorFile)
+			if filepath.Ext(localFile) != ".proto" || shadedFiles[localFile] {
+				continue
+			}
+			content, err := afero.ReadFile(m.fs, localFile)
+			if os.IsNotExist(err) {
+				// deleted by a patch
+				continue
+			} else if err != nil {
+				return err
+			}
+			name := localFile
+			if relative, err := filepath.Rel(vendorDir, localFile); err == nil {
+				name = filepath.ToSlash(relative)
+			}
+			found, err := protoutils.FindExtensionConflicts(extensions, name, content)
+			if err != nil {
+				return err
+			}
+			conflicts = append(conflicts, found...)
+		}
+	}
+	if len(conflicts) > 0 {
+		return &protoutils.ExtensionConflictsError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// the directory the files of the module are vendored into
+func (m *goModFactory) vendorRoot(mod *moduleWithImports) string {
+	return filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir, mod.module.Path)
+}
+
+// apply the patch files of every import to the files vendored from its module
+func (m *goModFactory) applyPatches(modules []*moduleWithImports, imports []*anyvendor.GoModImport) error {
+	for _, imp := range imports {
+		if len(imp.GetPatches()) == 0 {
+			continue
+		}
+		for _, mod := range modules {
+			if mod.module.Main || !importsModule(imp.GetPackage(), mod.module.Path) {
+				continue
+			}
+			licenses := map[string]bool{}
+			for licenseFile := range mod.licenses {
+				licenses[filepath.ToSlash(m.relativePath(mod, licenseFile))] = true
+			}
+			for _, patchFile := range imp.GetPatches() {
+				if !filepath.IsAbs(patchFile) {
+					patchFile = filepath.Join(m.WorkingDirectory, patchFile)
+				}
+				if err := applyPatchFile(m.fs, patchFile, m.vendorRoot(mod), licenses); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// apply every file diff in the patch file to the files in root, other than the license files
+func applyPatchFile(fs afero.Fs, patchFile, root string, licenses map[string]bool) error {
+	f, err := fs.Open(patchFile)
+	if err != nil {
+		return err
+	}
+	diffs, err := patch.Parse(f)
+	f.Close()
+	if err != nil {
+		return eris.Wrapf(err, "unable to parse patch %s", patchFile)
+	}
+	for _, diff := range diffs {
+		name := patch.Name(diff)
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if relative, err := filepath.Rel(root, target); err != nil || filepath.IsAbs(name) ||
+			relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return eris.Wrapf(PatchOutsideVendorRootError, "patch %s: %s", patchFile, name)
+		}
+		if licenses[path.Clean(name)] {
+			return eris.Wrapf(PatchLicenseFileError, "patch %s: %s", patchFile, name)
+		}
+		if diff.IsDelete {
+			redact.Logf("patch %s: deleting %s", patchFile, target)
+			if err := fs.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		var content []byte
+		// created files get the default mode, patched files keep theirs
+		mode := os.FileMode(0644)
+		if !diff.IsNew {
+			fileInfo, err := fs.Stat(target)
+			if err != nil {
+				return eris.Wrapf(err, "unable to apply patch %s", patchFile)
+			}
+			mode = fileInfo.Mode()
+			content, err = afero.ReadFile(fs, target)
+			if err != nil {
+				return eris.Wrapf(err, "unable to apply patch %s", patchFile)
+			}
+		}
+		patched, results, err := patch.Apply(content, diff)
+		if err != nil {
+			return eris.Wrapf(err, "patch %s no longer applies to %s", patchFile, target)
+		}
+		redact.Logf("patch %s: patching %s", patchFile, target)
+		for _, result := range results {
+			if result.Offset != 0 || result.Fuzz != 0 {
+				redact.Logf("patch %s: %s", patchFile, result)
+			}
+		}
+		if err := fs.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		if err := afero.WriteFile(fs, target, patched, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+generate a unified diff of each vendored file against the file as the config vendors it without any patches, so
+the edits the config makes itself, such as shading, rewritten imports and provenance headers, are not part of the
+diff. The vendored files may be absolute, or relative to the working directory.
+*/
+func (m *goModFactory) generatePatch(ctx context.Context, opts *anyvendor.Config, vendoredFiles []string, w io.Writer) error {
+	gatherOpts := m.gatherOptions(opts)
+	mods, err := m.gather(gatherOpts)
+	if err != nil {
+		return err
+	}
+	var unpatched []*anyvendor.GoModImport
+	for _, imp := range gatherOpts.MatchOptions {
+		imp = protov1.Clone(imp).(*anyvendor.GoModImport)
+		imp.Patches = nil
+		unpatched = append(unpatched, imp)
+	}
+	gatherOpts.MatchOptions = unpatched
+
+	sandbox := m.sandbox()
+	mods = cloneModules(mods)
+	// patches apply to the files as they are copied, so they are named by the path they are copied to
+	if err := sandbox.copy(mods); err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, mod := range mods {
+		for _, vendorFile := range mod.vendorList {
+			name, err := filepath.Rel(sandbox.vendorRoot(mod), sandbox.destination(mod, vendorFile))
+			if err != nil {
+				return err
+			}
+			names[vendorFile] = filepath.ToSlash(name)
+		}
+	}
+	if err := sandbox.vendor(ctx, opts, gatherOpts, mods); err != nil {
+		return err
+	}
+
+	for _, vendoredFile := range vendoredFiles {
+		if !filepath.IsAbs(vendoredFile) {
+			vendoredFile = filepath.Join(m.WorkingDirectory, vendoredFile)
+		}
+		vendorFile, ok := sandbox.vendorFile(mods, filepath.Clean(vendoredFile))
+		if !ok {
+			return eris.Errorf("%s was not vendored from any of the configured imports", vendoredFile)
+		}
+		original, err := afero.ReadFile(sandbox.fs, vendoredFile)
+		if err != nil {
+			return err
+		}
+		edited, err := afero.ReadFile(m.fs, vendoredFile)
+		if err != nil {
+			return err
+		}
+		// the provenance header is not part of the patched file
+		original, edited = provenance.Strip(vendoredFile, original), provenance.Strip(vendoredFile, edited)
+		name := names[vendorFile]
+		if _, err := io.WriteString(w, patch.Diff(name, name, original, edited)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find the file of a module which is vendored to the destination
+func (m *goModFactory) vendorFile(modules []*moduleWithImports, destination string) (string, bool) {
+	for _, mod := range modules {
+		for _, vendorFile := range mod.vendorList {
+			if filepath.Clean(m.destination(mod, vendorFile)) == destination {
+				return vendorFile, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,277 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/solo-io/anyvendor/pkg/redact"
+	"github.com/spf13/afero"
+)
+
+// Action is the change applying a plan makes to a file in the vendor dir.
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+	ActionDelete    Action = "delete"
+)
+
+// PlannedFile is a file in the vendor dir, along with the change applying the plan makes to it.
+type PlannedFile struct {
+	// the module the file is vendored from
+	Module string
+	// the absolute path of the file the destination is copied from, empty when it is deleted
+	Source string
+	// the absolute path of the file in the vendor dir, or of a source proto of the local module when its imports
+	// are rewritten in place
+	Destination string
+	Action      Action
+}
+
+/*
+Plan lists the changes Ensure would make to the files vendored into the vendor dir, once they have been patched,
+shaded, rewritten and stamped, as every step of the config which changes them is run in memory to plan it.
+*/
+type Plan struct {
+	// sorted by destination
+	Files []PlannedFile
+
+	workingDirectory string
+	opts             *anyvendor.Config
+	gatherOpts       goModOptions
+	modules          []*moduleWithImports
+}
+
+// returns the files in the plan with any of the actions
+func (p *Plan) filter(actions ...Action) []PlannedFile {
+	var files []PlannedFile
+	for _, file := range p.Files {
+		for _, action := range actions {
+			if file.Action == action {
+				files = append(files, file)
+				break
+			}
+		}
+	}
+	return files
+}
+
+// Changed returns true if applying the plan would change any file in the vendor dir.
+func (p *Plan) Changed() bool {
+	return len(p.filter(ActionCreate, ActionUpdate, ActionDelete)) > 0
+}
+
+/*
+Print writes a summary of the plan to w, with a line for every file which is created (+), updated (~), or
+deleted (-), followed by the number of files with each action. Secrets are redacted.
+*/
+func (p *Plan) Print(w io.Writer) error {
+	symbols := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}
+	counts := map[Action]int{}
+	for _, file := range p.Files {
+		counts[file.Action]++
+		symbol, ok := symbols[file.Action]
+		if !ok {
+			continue
+		}
+		destination := file.Destination
+		if relative, err := filepath.Rel(p.workingDirectory, destination); err == nil {
+			destination = relative
+		}
+		line := redact.String(fmt.Sprintf("%s %s (%s)\n", symbol, filepath.ToSlash(destination), file.Module))
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d to create, %d to update, %d unchanged, %d to delete\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionUnchanged], counts[ActionDelete])
+	return err
+}
+
+/*
+resolve the modules, and plan the changes vendoring them makes, by running every step of the config which changes
+the vendored files in a sandbox
+*/
+func (m *goModFactory) plan(ctx context.Context, opts *anyvendor.Config) (*Plan, error) {
+	plan, err := m.resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.planFiles(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// plan the changes vendoring the modules resolved by the plan makes to the files in the vendor dir
+func (m *goModFactory) planFiles(ctx context.Context, plan *Plan) error {
+	sandbox := m.sandbox()
+	mods := cloneModules(plan.modules)
+	if err := sandbox.vendor(ctx, plan.opts, plan.gatherOpts, mods); err != nil {
+		return err
+	}
+	planned := map[string]bool{}
+	for _, mod := range mods {
+		for _, vendorFile := range mod.vendorList {
+			destination := sandbox.destination(mod, vendorFile)
+			planned[destination] = true
+			content, err := afero.ReadFile(sandbox.fs, destination)
+			if os.IsNotExist(err) {
+				// deleted by a patch
+				if _, err := m.fs.Stat(destination); err == nil {
+					plan.Files = append(plan.Files, PlannedFile{
+						Module:      mod.module.Path,
+						Destination: destination,
+						Action:      ActionDelete,
+					})
+				}
+				continue
+			} else if err != nil {
+				return err
+			}
+			action, err := m.plannedAction(destination, content)
+			if err != nil {
+				return err
+			}
+			plan.Files = append(plan.Files, PlannedFile{
+				Module:      mod.module.Path,
+				Source:      vendorFile,
+				Destination: destination,
+				Action:      action,
+			})
+		}
+	}
+	if plan.opts.GetPatch().GetRewriteLocal() {
+		local, err := m.rewrittenLocalFiles(sandbox, mods)
+		if err != nil {
+			return err
+		}
+		plan.Files = append(plan.Files, local...)
+	}
+	stale, err := m.staleFiles(plan.modules, planned)
+	if err != nil {
+		return err
+	}
+	plan.Files = append(plan.Files, stale...)
+	sort.SliceStable(plan.Files, func(i, j int) bool {
+		return plan.Files[i].Destination < plan.Files[j].Destination
+	})
+	return nil
+}
+
+// returns the source proto files of the local module whose imports the sandbox rewrote in place
+func (m *goModFactory) rewrittenLocalFiles(sandbox *goModFactory, modules []*moduleWithImports) ([]PlannedFile, error) {
+	var files []PlannedFile
+	for _, mod := range modules {
+		if !mod.module.Main {
+			continue
+		}
+		for _, vendorFile := range mod.vendorList {
+			if filepath.Ext(vendorFile) != ".proto" {
+				continue
+			}
+			content, err := afero.ReadFile(sandbox.fs, vendorFile)
+			if err != nil {
+				return nil, err
+			}
+			action, err := m.plannedAction(vendorFile, content)
+			if err != nil {
+				return nil, err
+			}
+			if action == ActionUpdate {
+				files = append(files, PlannedFile{
+					Module:      mod.module.Path,
+					Source:      vendorFile,
+					Destination: vendorFile,
+					Action:      action,
+				})
+			}
+		}
+	}
+	return files, nil
+}
+
+// resolve the modules, returning a plan without any files, which vendors them without deleting anything
+func (m *goModFactory) resolve(opts *anyvendor.Config) (*Plan, error) {
+	gatherOpts := m.gatherOptions(opts)
+	mods, err := m.gather(gatherOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{
+		workingDirectory: m.WorkingDirectory,
+		opts:             opts,
+		gatherOpts:       gatherOpts,
+		modules:          mods,
+	}, nil
+}
+
+func (m *goModFactory) plannedAction(destination string, content []byte) (Action, error) {
+	existing, err := afero.ReadFile(m.fs, destination)
+	if os.IsNotExist(err) {
+		return ActionCreate, nil
+	} else if err != nil {
+		return "", err
+	}
+	if bytes.Equal(existing, content) {
+		return ActionUnchanged, nil
+	}
+	return ActionUpdate, nil
+}
+
+/*
+returns the files listed in the metadata file of every module, as written the last time it was vendored, which
+exist but are no longer vendored. Files anyvendor did not vendor, such as those vendored by other configs sharing the
+vendor dir, are never stale.
+*/
+func (m *goModFactory) staleFiles(modules []*moduleWithImports, planned map[string]bool) ([]PlannedFile, error) {
+	vendorDir := filepath.Join(m.WorkingDirectory, anyvendor.DefaultDepDir)
+	var stale []PlannedFile
+	for _, mod := range modules {
+		metadata, err := provenance.ReadMetadata(m.fs, m.vendorRoot(mod))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, file := range metadata.Files {
+			path := filepath.Join(vendorDir, filepath.FromSlash(file.Vendored))
+			if planned[path] || !strings.HasPrefix(path, vendorDir+string(filepath.Separator)) {
+				continue
+			}
+			if _, err := m.fs.Stat(path); os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			stale = append(stale, PlannedFile{
+				Module:      mod.module.Path,
+				Destination: path,
+				Action:      ActionDelete,
+			})
+		}
+	}
+	return stale, nil
+}
+
+// delete the stale files in the plan, along with their provenance sidecars
+func (m *goModFactory) deleteStaleFiles(plan *Plan) error {
+	for _, file := range plan.filter(ActionDelete) {
+		for _, path := range []string{file.Destination, file.Destination + provenance.SidecarSuffix} {
+			if err := m.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
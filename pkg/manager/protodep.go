@@ -2,8 +2,12 @@ package manager
 
 import (
 	"context"
+	"io"
 
+	"github.com/rotisserie/eris"
 	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/outdated"
+	"github.com/solo-io/anyvendor/pkg/transform"
 )
 
 /*
@@ -20,6 +24,7 @@ a given anyvendor config, as outlined by the `anyvendor.Config` object.
 */
 type Manager struct {
 	depFactories []depFactory
+	goMod        *goModFactory
 }
 
 func NewManager(ctx context.Context, cwd string) (*Manager, error) {
@@ -36,6 +41,7 @@ func NewManager(ctx context.Context, cwd string) (*Manager, error) {
 		depFactories: []depFactory{
 			goMod,
 		},
+		goMod: goMod,
 	}, nil
 }
 
@@ -51,13 +57,27 @@ func NewManagerWithSettings(ctx context.Context, settings *anyvendor.FactorySett
 		depFactories: []depFactory{
 			goMod,
 		},
+		goMod: goMod,
 	}, nil
 }
 
-func (m *Manager) Ensure(ctx context.Context, opts *anyvendor.Config) error {
+var UnresolvedIncludesError = eris.New("config includes other files, load it with config.LoadConfig to merge them")
+
+// validate the config, which must not include other files, as the manager does not read files
+func validateConfig(opts *anyvendor.Config) error {
 	if err := opts.Validate(); err != nil {
 		return err
 	}
+	if len(opts.GetIncludes()) > 0 {
+		return eris.Wrapf(UnresolvedIncludesError, "includes: %v", opts.GetIncludes())
+	}
+	return nil
+}
+
+func (m *Manager) Ensure(ctx context.Context, opts *anyvendor.Config) error {
+	if err := validateConfig(opts); err != nil {
+		return err
+	}
 	for _, v := range m.depFactories {
 		if err := v.Ensure(ctx, opts); err != nil {
 			return err
@@ -65,3 +85,74 @@ func (m *Manager) Ensure(ctx context.Context, opts *anyvendor.Config) error {
 	}
 	return nil
 }
+
+/*
+Plan resolves the imports of the config, and returns the changes Ensure would make to the files in the vendor dir,
+without changing anything. The steps of the config which change the vendored files, such as patches and shading,
+are run in memory, so the plan shows the files as they will be vendored.
+*/
+func (m *Manager) Plan(ctx context.Context, opts *anyvendor.Config) (*Plan, error) {
+	if err := validateConfig(opts); err != nil {
+		return nil, err
+	}
+	return m.goMod.plan(ctx, opts)
+}
+
+/*
+Apply vendors the files of a plan returned by Plan, deleting the files it found to be stale, and then runs the
+rest of the config, exactly as Ensure would. Ensure itself never deletes files.
+*/
+func (m *Manager) Apply(ctx context.Context, plan *Plan) error {
+	return m.goMod.apply(ctx, plan)
+}
+
+/*
+Diff writes a unified diff of the files currently vendored from the module, against the files which would be
+vendored from the candidate version of it, to w. The version may be any version understood by go mod download,
+such as v1.2.3, latest, or a commit, and is downloaded with the go.mod file of the working directory. Files are
+named relative to the vendored root of the module, and the candidate files are vendored in memory exactly as
+Ensure would vendor them, so they are patched, shaded and rewritten as the current ones.
+*/
+func (m *Manager) Diff(ctx context.Context, opts *anyvendor.Config, module, version string, w io.Writer) error {
+	if err := validateConfig(opts); err != nil {
+		return err
+	}
+	return m.goMod.diffCandidate(ctx, opts, module, version, w)
+}
+
+/*
+Outdated reports the version of every module files are vendored from, along with the newest version it can be
+upgraded to, from go list -m -u. The modules are listed without enforcing the policy or resolving imports.
+*/
+func (m *Manager) Outdated(ctx context.Context, opts *anyvendor.Config) (outdated.Report, error) {
+	if err := validateConfig(opts); err != nil {
+		return nil, err
+	}
+	return m.goMod.outdated(opts)
+}
+
+/*
+AddTransformer runs the transformer on every file vendored from module, or from a module nested under its path
+other than another major version of it, such as module/v2, after any transforms configured for their imports. An
+empty module matches every module. Files vendored from the local module are never transformed.
+*/
+func (m *Manager) AddTransformer(module string, transformer transform.Transformer) {
+	m.goMod.transformers = append(m.goMod.transformers, moduleTransformer{
+		module:      module,
+		transformer: transformer,
+	})
+}
+
+/*
+GeneratePatch writes a unified diff of the local edits made to each vendored file, relative to the file as the
+config vendors it without any patches, to w. The unpatched files are vendored in memory, so the edits the config
+makes itself, such as shading and rewritten imports, are not part of the diff, while the edits of its patches are.
+The output can be saved as a patch file, replacing the patches of the import, so the edits are re-applied every
+time the import is vendored.
+*/
+func (m *Manager) GeneratePatch(ctx context.Context, opts *anyvendor.Config, vendoredFiles []string, w io.Writer) error {
+	if err := validateConfig(opts); err != nil {
+		return err
+	}
+	return m.goMod.generatePatch(ctx, opts, vendoredFiles, w)
+}
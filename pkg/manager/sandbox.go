@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+/*
+returns a filesystem which reads base, but keeps every change in memory, so the steps of a config can be run
+without touching the tree. Unlike an afero.CopyOnWriteFs on its own, a file which has been written and then removed,
+such as a file moved by shading or deleted by a patch, no longer exists, even if it exists in base.
+*/
+func newSandboxFs(base afero.Fs) afero.Fs {
+	layer := &sandboxLayer{Fs: afero.NewMemMapFs(), written: map[string]bool{}}
+	return afero.NewCopyOnWriteFs(&sandboxBase{Fs: afero.NewReadOnlyFs(base), layer: layer}, layer)
+}
+
+// the in memory layer of a sandbox, which records every file written to it
+type sandboxLayer struct {
+	afero.Fs
+	written map[string]bool
+}
+
+func (l *sandboxLayer) Create(name string) (afero.File, error) {
+	l.written[filepath.Clean(name)] = true
+	return l.Fs.Create(name)
+}
+
+func (l *sandboxLayer) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+		l.written[filepath.Clean(name)] = true
+	}
+	return l.Fs.OpenFile(name, flag, perm)
+}
+
+// the tree beneath a sandbox, which hides the files written to the layer, so they stay removed once removed
+type sandboxBase struct {
+	afero.Fs
+	layer *sandboxLayer
+}
+
+func (b *sandboxBase) hidden(op, name string) error {
+	if b.layer.written[filepath.Clean(name)] {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	return nil
+}
+
+func (b *sandboxBase) Open(name string) (afero.File, error) {
+	if err := b.hidden("open", name); err != nil {
+		return nil, err
+	}
+	return b.Fs.Open(name)
+}
+
+func (b *sandboxBase) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if err := b.hidden("open", name); err != nil {
+		return nil, err
+	}
+	return b.Fs.OpenFile(name, flag, perm)
+}
+
+func (b *sandboxBase) Stat(name string) (os.FileInfo, error) {
+	if err := b.hidden("stat", name); err != nil {
+		return nil, err
+	}
+	return b.Fs.Stat(name)
+}
+
+// returns a copy of the factory which reads the tree, but writes to memory, see newSandboxFs
+func (m *goModFactory) sandbox() *goModFactory {
+	sandbox := *m
+	sandbox.fs = newSandboxFs(m.fs)
+	sandbox.fileCopier = NewCopier(sandbox.fs, m.skipPatterns)
+	return &sandbox
+}
+
+// returns copies of the modules which can be vendored without changing the originals
+func cloneModules(modules []*moduleWithImports) []*moduleWithImports {
+	clones := make([]*moduleWithImports, 0, len(modules))
+	for _, mod := range modules {
+		clone := *mod
+		clone.vendorList = append([]string(nil), mod.vendorList...)
+		clone.renamed = nil
+		clones = append(clones, &clone)
+	}
+	return clones
+}
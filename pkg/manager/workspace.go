@@ -0,0 +1,194 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/config"
+	"github.com/solo-io/anyvendor/pkg/redact"
+)
+
+// WorkspaceOptions configures how the configs beneath the root of a workspace are found and run.
+type WorkspaceOptions struct {
+	// globs of the directories and configs beneath the root to skip, see config.Discover
+	SkipPatterns []string
+	// the most configs run at once, defaults to the number of CPUs
+	Parallelism int
+}
+
+// WorkspaceResult is the outcome of running a single config of a workspace.
+type WorkspaceResult struct {
+	// the path of the config file
+	Config string
+	// the directory the manager of the config ran in, empty if the config could not be loaded
+	Cwd string
+	Err error
+}
+
+// WorkspaceError lists the configs of a workspace which failed, in the order they were discovered.
+type WorkspaceError struct {
+	Failed []WorkspaceResult
+}
+
+func (e *WorkspaceError) Error() string {
+	lines := make([]string, 0, len(e.Failed))
+	for _, result := range e.Failed {
+		lines = append(lines, fmt.Sprintf("\t%s: %s", result.Config, result.Err.Error()))
+	}
+	return redact.String(fmt.Sprintf("%d config(s) failed:\n%s", len(e.Failed), strings.Join(lines, "\n")))
+}
+
+func (e *WorkspaceError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, result := range e.Failed {
+		errs = append(errs, result.Err)
+	}
+	return errs
+}
+
+/*
+RunWorkspace finds every config beneath root with config.Discover, and calls run with each of them, and a manager
+for it, running at most opts.Parallelism at once. The manager is created from the settings of the config, with the
+cwd resolved against the directory of the config file, which is also the default. A result is returned for every
+config, in the order they were discovered, and a *WorkspaceError when any of them failed to load or run.
+
+Configs which resolve to the same cwd share its vendor dir, so they are run one after another, in the order they
+were discovered, while configs with different cwds run concurrently.
+*/
+func RunWorkspace(
+	ctx context.Context,
+	root string,
+	opts WorkspaceOptions,
+	run func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error,
+) ([]WorkspaceResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	paths, err := config.Discover(root, opts.SkipPatterns)
+	if err != nil {
+		return nil, err
+	}
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	results := make([]WorkspaceResult, len(paths))
+	configs := make([]workspaceConfig, len(paths))
+	// the indexes of the configs sharing each cwd, in the order the cwds were first seen
+	var groups [][]int
+	groupOf := map[string]int{}
+	for i, path := range paths {
+		results[i].Config = path
+		if configs[i], results[i].Err = loadWorkspaceConfig(path); results[i].Err != nil {
+			continue
+		}
+		cwd := configs[i].settings.GetCwd()
+		group, ok := groupOf[cwd]
+		if !ok {
+			group = len(groups)
+			groupOf[cwd] = group
+			groups = append(groups, nil)
+		}
+		groups[group] = append(groups[group], i)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for _, group := range groups {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for _, i := range group {
+				results[i].Err = ctx.Err()
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(group []int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, i := range group {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Cwd, results[i].Err = configs[i].run(ctx, run)
+			}
+		}(group)
+	}
+	wg.Wait()
+
+	var failed []WorkspaceResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &WorkspaceError{Failed: failed}
+	}
+	return results, nil
+}
+
+// EnsureWorkspace ensures every config beneath root, see RunWorkspace.
+func EnsureWorkspace(ctx context.Context, root string, opts WorkspaceOptions) ([]WorkspaceResult, error) {
+	return RunWorkspace(ctx, root, opts, func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error {
+		return m.Ensure(ctx, cfg)
+	})
+}
+
+// a config of a workspace, along with the settings its manager is created from
+type workspaceConfig struct {
+	cfg      *anyvendor.Config
+	settings *anyvendor.FactorySettings
+}
+
+func loadWorkspaceConfig(path string) (workspaceConfig, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return workspaceConfig{}, err
+	}
+	settings, err := workspaceSettings(path, cfg)
+	if err != nil {
+		return workspaceConfig{}, err
+	}
+	return workspaceConfig{cfg: cfg, settings: settings}, nil
+}
+
+// run the config, returning the directory its manager ran in
+func (c workspaceConfig) run(
+	ctx context.Context,
+	run func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error,
+) (string, error) {
+	m, err := NewManagerWithSettings(ctx, c.settings)
+	if err != nil {
+		return "", err
+	}
+	return m.goMod.WorkingDirectory, run(ctx, m, c.cfg)
+}
+
+// returns the settings of the config, with the cwd made absolute, resolving it against the directory of the config file
+func workspaceSettings(path string, cfg *anyvendor.Config) (*anyvendor.FactorySettings, error) {
+	settings := &anyvendor.FactorySettings{}
+	if cfg.GetSettings() != nil {
+		settings = protov1.Clone(cfg.GetSettings()).(*anyvendor.FactorySettings)
+	}
+	if !filepath.IsAbs(settings.GetCwd()) {
+		cwd, err := filepath.Abs(filepath.Join(filepath.Dir(path), settings.GetCwd()))
+		if err != nil {
+			return nil, err
+		}
+		settings.Cwd = cwd
+	}
+	settings.Cwd = filepath.Clean(settings.GetCwd())
+	return settings, nil
+}
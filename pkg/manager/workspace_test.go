@@ -0,0 +1,182 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/config"
+)
+
+var _ = Describe("workspace", func() {
+	var root string
+
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+	})
+
+	It("runs every config with a manager in its directory", func() {
+		write("anyvendor.yaml", "settings:\n  cwd: src\n")
+		write("a/anyvendor.yaml", "resolveImports: true\n")
+		write("b/anyvendor.textproto", "settings { cwd: \""+filepath.Join(root, "elsewhere")+"\" }\n")
+		write("c/node_modules/anyvendor.yaml", "")
+
+		var (
+			mu   sync.Mutex
+			seen = map[string]*anyvendor.Config{}
+		)
+		results, err := RunWorkspace(context.Background(), root, WorkspaceOptions{
+			SkipPatterns: []string{"**/node_modules/**"},
+		}, func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error {
+			mu.Lock()
+			defer mu.Unlock()
+			seen[m.goMod.WorkingDirectory] = cfg
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(Equal([]WorkspaceResult{
+			{Config: filepath.Join(root, "anyvendor.yaml"), Cwd: filepath.Join(root, "src")},
+			{Config: filepath.Join(root, "a/anyvendor.yaml"), Cwd: filepath.Join(root, "a")},
+			{Config: filepath.Join(root, "b/anyvendor.textproto"), Cwd: filepath.Join(root, "elsewhere")},
+		}))
+		Expect(seen).To(HaveLen(3))
+		Expect(seen[filepath.Join(root, "a")].GetResolveImports()).To(BeTrue())
+	})
+
+	It("runs at most the parallelism at once", func() {
+		for _, dir := range []string{"a", "b", "c", "d", "e"} {
+			write(dir+"/anyvendor.yaml", "")
+		}
+		var (
+			mu                 sync.Mutex
+			running, most, ran int
+		)
+		_, err := RunWorkspace(context.Background(), root, WorkspaceOptions{Parallelism: 2},
+			func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error {
+				mu.Lock()
+				running++
+				if running > most {
+					most = running
+				}
+				mu.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mu.Lock()
+				running--
+				ran++
+				mu.Unlock()
+				return nil
+			})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ran).To(Equal(5))
+		Expect(most).To(Equal(2))
+	})
+
+	It("runs the configs sharing a cwd one after another", func() {
+		write("anyvendor.yaml", "settings:\n  cwd: a\n")
+		write("a/anyvendor.yaml", "resolveImports: true\n")
+		write("b/anyvendor.yaml", "settings:\n  cwd: ../a/.\n")
+		write("c/anyvendor.yaml", "")
+		var (
+			mu      sync.Mutex
+			running = map[string]int{}
+			most    int
+			order   []*anyvendor.Config
+		)
+		_, err := RunWorkspace(context.Background(), root, WorkspaceOptions{Parallelism: 4},
+			func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error {
+				cwd := m.goMod.WorkingDirectory
+				mu.Lock()
+				running[cwd]++
+				if running[cwd] > most {
+					most = running[cwd]
+				}
+				if cwd == filepath.Join(root, "a") {
+					order = append(order, cfg)
+				}
+				mu.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mu.Lock()
+				running[cwd]--
+				mu.Unlock()
+				return nil
+			})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(most).To(Equal(1))
+		Expect(order).To(HaveLen(3))
+		Expect(order[1].GetResolveImports()).To(BeTrue())
+	})
+
+	It("aggregates the errors of every config", func() {
+		fakeErr := eris.New("test error")
+		write("a/anyvendor.yaml", "unknown: true\n")
+		write("b/anyvendor.yaml", "")
+		write("c/anyvendor.yaml", "")
+		results, err := RunWorkspace(context.Background(), root, WorkspaceOptions{},
+			func(ctx context.Context, m *Manager, cfg *anyvendor.Config) error {
+				if filepath.Base(m.goMod.WorkingDirectory) == "c" {
+					return fakeErr
+				}
+				return nil
+			})
+		Expect(err).To(HaveOccurred())
+		Expect(results).To(HaveLen(3))
+		Expect(results[1].Err).NotTo(HaveOccurred())
+
+		workspaceErr, ok := err.(*WorkspaceError)
+		Expect(ok).To(BeTrue())
+		Expect(workspaceErr.Failed).To(HaveLen(2))
+		Expect(workspaceErr.Failed[0].Config).To(Equal(filepath.Join(root, "a/anyvendor.yaml")))
+		var configErr *config.Error
+		Expect(errors.As(workspaceErr.Failed[0].Err, &configErr)).To(BeTrue())
+		Expect(configErr.Line).To(Equal(1))
+		Expect(errors.Is(err, fakeErr)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("2 config(s) failed"))
+	})
+
+	It("vendors the module of the cwd, rather than the module of the process", func() {
+		write("other/go.mod", "module example.com/other\n\ngo 1.24\n")
+		write("other/api/other.proto", "syntax = \"proto3\";\n")
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Join(wd, "..", "..", "go.mod")).To(BeAnExistingFile())
+
+		m, err := NewManagerWithSettings(context.Background(), &anyvendor.FactorySettings{Cwd: filepath.Join(root, "other")})
+		Expect(err).NotTo(HaveOccurred())
+		err = m.Ensure(context.Background(), &anyvendor.Config{
+			Local:        &anyvendor.Local{Patterns: []string{"api/*.proto"}},
+			SkipLicenses: true,
+			SkipMetadata: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Join(root, "other", anyvendor.DefaultDepDir, "example.com", "other", "api", "other.proto")).
+			To(BeAnExistingFile())
+		Expect(filepath.Join(wd, anyvendor.DefaultDepDir, "example.com")).NotTo(BeADirectory())
+	})
+
+	It("ensures the configs of separate modules", func() {
+		for _, module := range []string{"a", "b"} {
+			write(module+"/go.mod", "module example.com/"+module+"\n\ngo 1.24\n")
+			write(module+"/api/"+module+".proto", "syntax = \"proto3\";\n")
+			write(module+"/anyvendor.yaml", "local:\n  patterns:\n  - api/*.proto\nskipLicenses: true\nskipMetadata: true\n")
+		}
+		_, err := EnsureWorkspace(context.Background(), root, WorkspaceOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, module := range []string{"a", "b"} {
+			Expect(filepath.Join(root, module, anyvendor.DefaultDepDir, "example.com", module, "api", module+".proto")).
+				To(BeAnExistingFile())
+		}
+	})
+})
@@ -24,3 +24,17 @@ type Module struct {
 type ModuleError struct {
 	Err string // the error itself
 }
+
+// mirror of https://golang.org/src/cmd/go/internal/modcmd/download.go
+// used to unmarshal output of `go mod download -json`
+type ModuleDownload struct {
+	Path     string // module path
+	Version  string // module version
+	Error    string // error loading module
+	Info     string // absolute path to cached .info file
+	GoMod    string // absolute path to cached .mod file
+	Zip      string // absolute path to cached .zip file
+	Dir      string // absolute path to cached source root directory
+	Sum      string // checksum for path, version (as in go.sum)
+	GoModSum string // checksum for go.mod (as in go.sum)
+}
@@ -19,6 +19,8 @@ var (
 	EmptyFileError = eris.New("empty file supplied, must be")
 
 	UnableToListPackagesError = eris.New("unable to list dependencies for current go.mod packages")
+
+	UnableToDownloadModuleError = eris.New("unable to download module")
 )
 
 /*
@@ -51,7 +53,13 @@ Ex: /path/to/solo-kit/go.mod
 Will return /dev/null on unix if not in a go.mod package
 */
 func GetCurrentModPackageFile() (string, error) {
+	return GetModPackageFile("")
+}
+
+// Returns the go.mod file of the module containing dir, or of the current directory if dir is empty.
+func GetModPackageFile(dir string) (string, error) {
 	cmd := exec.Command("go", "env", "GOMOD")
+	cmd.Dir = dir
 	modBytes, err := cmd.Output()
 	if err != nil {
 		return "", eris.Wrap(ModPackageFileError, err.Error())
@@ -64,13 +72,18 @@ func GetCurrentModPackageFile() (string, error) {
 }
 
 func GetCurrentPackageListAll() (*bytes.Buffer, error) {
-	return goModListWrapper(nil, "")
+	return goModListWrapper("", nil, "")
 }
 
 func GetCurrentPackageListJson(modules []string) ([]*Module, error) {
+	return GetPackageListJson("", modules)
+}
+
+// Lists the modules as they are required by the module containing dir, or the current directory if dir is empty.
+func GetPackageListJson(dir string, modules []string) ([]*Module, error) {
 	var packages []*Module
 	for _, v := range modules {
-		jsonByt, err := goModListWrapper([]string{"-json"}, v)
+		jsonByt, err := goModListWrapper(dir, []string{"-json"}, v)
 		if err != nil {
 			return nil, err
 		}
@@ -83,7 +96,67 @@ func GetCurrentPackageListJson(modules []string) ([]*Module, error) {
 	return packages, nil
 }
 
-func goModListWrapper(args []string, packageName string) (*bytes.Buffer, error) {
+/*
+Lists the modules along with the newest version each can be upgraded to, in their Update field, which is nil when
+the module is already at the newest version.
+*/
+func GetModuleUpdates(modules []string) ([]*Module, error) {
+	return GetModuleUpdatesIn("", modules)
+}
+
+// Lists the updates to the modules required by the module containing dir, or the current directory if dir is empty.
+func GetModuleUpdatesIn(dir string, modules []string) ([]*Module, error) {
+	var packages []*Module
+	for _, v := range modules {
+		jsonByt, err := goModListWrapper(dir, []string{"-u", "-json"}, v)
+		if err != nil {
+			return nil, err
+		}
+		var jsonModule Module
+		if err := json.Unmarshal(jsonByt.Bytes(), &jsonModule); err != nil {
+			return nil, err
+		}
+		packages = append(packages, &jsonModule)
+	}
+	return packages, nil
+}
+
+/*
+Downloads the version of the module to the module cache, without changing the go.mod file, and returns where it
+was downloaded to. The version may be any version query, such as latest, a branch or a commit.
+*/
+func DownloadModule(module, version string) (*ModuleDownload, error) {
+	return DownloadModuleIn("", module, version)
+}
+
+/*
+Downloads the version of the module, as go mod download would when run in dir, or the current directory if dir is
+empty, so the go.mod file, and the GOFLAGS, GOPROXY and GOPRIVATE settings of the module containing dir apply.
+*/
+func DownloadModuleIn(dir, module, version string) (*ModuleDownload, error) {
+	downloadCmd := exec.Command("go", "mod", "download", "-json", module+"@"+version)
+	downloadCmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	downloadCmd.Stdout = &stdout
+	downloadCmd.Stderr = &stderr
+	runErr := downloadCmd.Run()
+	var download ModuleDownload
+	if err := json.Unmarshal(stdout.Bytes(), &download); err != nil {
+		if runErr != nil {
+			return nil, eris.Wrapf(UnableToDownloadModuleError, "%s@%s: %s", module, version, stderr.String())
+		}
+		return nil, err
+	}
+	if download.Error != "" {
+		return nil, eris.Wrapf(UnableToDownloadModuleError, "%s@%s: %s", module, version, download.Error)
+	}
+	if runErr != nil {
+		return nil, eris.Wrapf(UnableToDownloadModuleError, "%s@%s: %s", module, version, stderr.String())
+	}
+	return &download, nil
+}
+
+func goModListWrapper(dir string, args []string, packageName string) (*bytes.Buffer, error) {
 	args = append([]string{"list", "-m"}, args...)
 	if packageName != "" {
 		args = append(args, packageName)
@@ -91,6 +164,7 @@ func goModListWrapper(args []string, packageName string) (*bytes.Buffer, error)
 		args = append(args, "all")
 	}
 	packageListCmd := exec.Command("go", args...)
+	packageListCmd.Dir = dir
 	modPackageReader := &bytes.Buffer{}
 	packageListCmd.Stdout = modPackageReader
 	packageListCmd.Stderr = modPackageReader
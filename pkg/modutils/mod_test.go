@@ -1,6 +1,8 @@
 package modutils
 
 import (
+	"os"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rotisserie/eris"
@@ -19,6 +21,17 @@ var _ = Describe("modutils", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(pacakgeName).To(Equal("github.com/solo-io/anyvendor"))
 	})
+	It("can get the mod file of another directory", func() {
+		name, err := GetModPackageFile("../../anyvendor")
+		Expect(err).NotTo(HaveOccurred())
+		current, err := GetCurrentModPackageFile()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal(current))
+
+		_, err = GetModPackageFile(os.TempDir())
+		Expect(err).To(HaveOccurred())
+		Expect(eris.Is(err, NonGoModPackageError)).To(BeTrue())
+	})
 	It("can list the packages used by this module", func() {
 		_, err := GetCurrentPackageListAll()
 		Expect(err).NotTo(HaveOccurred())
@@ -32,4 +45,14 @@ var _ = Describe("modutils", func() {
 		Expect(list[0].Path).To(Equal("github.com/solo-io/anyvendor"))
 		Expect(err).NotTo(HaveOccurred())
 	})
+	It("can download a version of a module", func() {
+		download, err := DownloadModule("github.com/envoyproxy/protoc-gen-validate", "v0.6.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(download.Version).To(Equal("v0.6.1"))
+		Expect(download.Dir).To(BeADirectory())
+
+		_, err = DownloadModule("github.com/envoyproxy/protoc-gen-validate", "v0.0.0-not-a-version")
+		Expect(err).To(HaveOccurred())
+		Expect(eris.Is(err, UnableToDownloadModuleError)).To(BeTrue())
+	})
 })
@@ -0,0 +1,78 @@
+package outdated
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/solo-io/anyvendor/pkg/redact"
+)
+
+// Source is the current and latest version of a source files are vendored from.
+type Source struct {
+	// one of the provenance.SourceType constants
+	Type string `json:"type"`
+	// the module path, or repository URL
+	Name string `json:"name"`
+	// the module version, or git tag or commit, currently vendored
+	Current string `json:"current"`
+	// the newest version available upstream, empty when it is not known
+	Latest string `json:"latest,omitempty"`
+	// the newest version matching the constraint of a git repository, if it has one
+	LatestMatching string `json:"latestMatching,omitempty"`
+	// true if a newer version than the current one is available
+	Outdated bool `json:"outdated"`
+}
+
+// Report lists the versions of every vendored source.
+type Report []Source
+
+// Outdated returns the sources in the report which are outdated.
+func (r Report) Outdated() Report {
+	var outdated Report
+	for _, source := range r {
+		if source.Outdated {
+			outdated = append(outdated, source)
+		}
+	}
+	return outdated
+}
+
+// WriteText writes the report as a table, with a row for every source. Secrets are redacted.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tTYPE\tCURRENT\tLATEST\tLATEST MATCHING\tOUTDATED")
+	for _, source := range r.redacted() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", source.Name, source.Type, orDash(source.Current),
+			orDash(source.Latest), orDash(source.LatestMatching), source.Outdated)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as a json array of sources. Secrets are redacted.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.redacted())
+}
+
+// returns a copy of the report with the secrets redacted from every source
+func (r Report) redacted() Report {
+	redacted := Report{}
+	for _, source := range r {
+		source.Name = redact.String(source.Name)
+		source.Current = redact.String(source.Current)
+		source.Latest = redact.String(source.Latest)
+		source.LatestMatching = redact.String(source.LatestMatching)
+		redacted = append(redacted, source)
+	}
+	return redacted
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,13 @@
+package outdated_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOutdated(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Outdated Suite")
+}
@@ -0,0 +1,57 @@
+package outdated_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/pkg/outdated"
+	"github.com/solo-io/anyvendor/pkg/redact"
+)
+
+var _ = Describe("Report", func() {
+	report := outdated.Report{
+		{Type: "gomod", Name: "github.com/envoyproxy/protoc-gen-validate", Current: "v0.6.1", Latest: "v1.3.3", Outdated: true},
+		{Type: "gomod", Name: "github.com/solo-io/solo-kit", Current: "v0.30.0", Latest: "v0.30.0"},
+		{Type: "git", Name: "https://github.com/foo/bar", Current: "v1.4.0", Latest: "v2.0.0", LatestMatching: "v1.5.1", Outdated: true},
+	}
+
+	It("filters the outdated sources", func() {
+		Expect(report.Outdated()).To(Equal(outdated.Report{report[0], report[2]}))
+	})
+	It("writes a table", func() {
+		var text strings.Builder
+		Expect(report.WriteText(&text)).To(Succeed())
+		lines := strings.Split(strings.TrimSuffix(text.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(4))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"SOURCE", "TYPE", "CURRENT", "LATEST", "LATEST", "MATCHING", "OUTDATED"}))
+		Expect(strings.Fields(lines[2])).To(Equal([]string{"github.com/solo-io/solo-kit", "gomod", "v0.30.0", "v0.30.0", "-", "false"}))
+		Expect(strings.Fields(lines[3])).To(Equal([]string{"https://github.com/foo/bar", "git", "v1.4.0", "v2.0.0", "v1.5.1", "true"}))
+		// the columns are aligned
+		Expect(strings.Index(lines[1], "gomod")).To(Equal(strings.Index(lines[0], "TYPE")))
+	})
+	It("writes json", func() {
+		var out strings.Builder
+		Expect(report.WriteJSON(&out)).To(Succeed())
+		var decoded outdated.Report
+		Expect(json.Unmarshal([]byte(out.String()), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(report))
+
+		out.Reset()
+		Expect(outdated.Report(nil).WriteJSON(&out)).To(Succeed())
+		Expect(out.String()).To(Equal("[]\n"))
+	})
+	It("redacts secrets", func() {
+		redact.Register("hunter2")
+		defer redact.Reset()
+		secret := outdated.Report{{Type: "git", Name: "https://hunter2@github.com/foo/bar", Current: "v1.4.0"}}
+
+		var text, out strings.Builder
+		Expect(secret.WriteText(&text)).To(Succeed())
+		Expect(text.String()).To(ContainSubstring("https://[REDACTED]@github.com/foo/bar"))
+		Expect(secret.WriteJSON(&out)).To(Succeed())
+		Expect(out.String()).NotTo(ContainSubstring("hunter2"))
+		Expect(secret[0].Name).To(ContainSubstring("hunter2"))
+	})
+})
@@ -0,0 +1,92 @@
+package patch
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// number of unchanged lines shown around each change
+const contextLines = 3
+
+/*
+Diff returns a unified diff, in the format of git diff, which turns the old content into the new content. It is
+accepted by Parse, git apply and patch -p1. Either name may be /dev/null, when the file is created or deleted. An
+empty string is returned when the contents are identical. The diff is computed and formatted by go-git.
+*/
+func Diff(oldName, newName string, old, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+	filePatch := &filePatch{}
+	if oldName != devNull {
+		filePatch.from = &file{name: oldName, content: old}
+	}
+	if newName != devNull {
+		filePatch.to = &file{name: newName, content: new}
+	}
+	for _, d := range diff.Do(string(old), string(new)) {
+		filePatch.chunks = append(filePatch.chunks, &chunk{content: d.Text, op: operations[d.Type]})
+	}
+	var b strings.Builder
+	// writing to a strings.Builder never fails
+	_ = fdiff.NewUnifiedEncoder(&b, contextLines).Encode(&unifiedPatch{files: []fdiff.FilePatch{filePatch}})
+	return b.String()
+}
+
+var operations = map[diffmatchpatch.Operation]fdiff.Operation{
+	diffmatchpatch.DiffEqual:  fdiff.Equal,
+	diffmatchpatch.DiffInsert: fdiff.Add,
+	diffmatchpatch.DiffDelete: fdiff.Delete,
+}
+
+// implementations of the go-git diff interfaces the unified encoder formats
+
+type unifiedPatch struct {
+	files []fdiff.FilePatch
+}
+
+func (p *unifiedPatch) FilePatches() []fdiff.FilePatch { return p.files }
+func (p *unifiedPatch) Message() string                { return "" }
+
+type filePatch struct {
+	from, to *file
+	chunks   []fdiff.Chunk
+}
+
+func (p *filePatch) IsBinary() bool        { return false }
+func (p *filePatch) Chunks() []fdiff.Chunk { return p.chunks }
+
+func (p *filePatch) Files() (fdiff.File, fdiff.File) {
+	// the encoder checks for nil interfaces, not nil pointers
+	var from, to fdiff.File
+	if p.from != nil {
+		from = p.from
+	}
+	if p.to != nil {
+		to = p.to
+	}
+	return from, to
+}
+
+type file struct {
+	name    string
+	content []byte
+}
+
+func (f *file) Hash() plumbing.Hash     { return plumbing.ComputeHash(plumbing.BlobObject, f.content) }
+func (f *file) Mode() filemode.FileMode { return filemode.Regular }
+func (f *file) Path() string            { return f.name }
+
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c *chunk) Content() string       { return c.content }
+func (c *chunk) Type() fdiff.Operation { return c.op }
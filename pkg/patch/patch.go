@@ -0,0 +1,189 @@
+package patch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/rotisserie/eris"
+)
+
+const devNull = "/dev/null"
+
+var UnsupportedPatchError = eris.New("unsupported patch")
+
+/*
+Parse parses a unified diff, such as the output of `diff -u` or `git diff`, into the diffs of each file, with
+go-gitdiff. The a/ and b/ prefixes of the names in diffs without a git header are removed, as patch -p1 would.
+Binary diffs, renames and copies are not supported.
+*/
+func Parse(r io.Reader) ([]*gitdiff.File, error) {
+	files, _, err := gitdiff.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file.IsBinary || file.IsRename || file.IsCopy {
+			return nil, eris.Wrapf(UnsupportedPatchError, "%s is a binary diff, rename or copy", Name(file))
+		}
+		// diffs with a git header always have an index line, which go-gitdiff removes the prefixes of
+		if file.OldOIDPrefix == "" && file.NewOIDPrefix == "" {
+			file.OldName = strings.TrimPrefix(file.OldName, "a/")
+			file.NewName = strings.TrimPrefix(file.NewName, "b/")
+		}
+	}
+	return files, nil
+}
+
+// Name returns the path of the file the diff applies to.
+func Name(file *gitdiff.File) string {
+	if file.IsDelete {
+		return file.OldName
+	}
+	return file.NewName
+}
+
+// HunkResult describes where a hunk was applied.
+type HunkResult struct {
+	// 1 based index of the hunk in the file diff
+	Hunk int
+	// line the hunk was applied at, in the patched file
+	Line int
+	// number of lines away from the position in the hunk header
+	Offset int
+	// number of context lines which were ignored to apply the hunk
+	Fuzz int
+}
+
+func (r HunkResult) String() string {
+	s := fmt.Sprintf("hunk #%d succeeded at %d", r.Hunk, r.Line)
+	switch {
+	case r.Offset != 0 && r.Fuzz != 0:
+		s += fmt.Sprintf(" with fuzz %d (offset %d lines)", r.Fuzz, r.Offset)
+	case r.Offset != 0:
+		s += fmt.Sprintf(" (offset %d lines)", r.Offset)
+	case r.Fuzz != 0:
+		s += fmt.Sprintf(" with fuzz %d", r.Fuzz)
+	}
+	return s
+}
+
+// ApplyError is returned when a hunk can not be applied to a file.
+type ApplyError struct {
+	File string
+	// 1 based index of the hunk in the file diff
+	Hunk int
+	// line of the original file the hunk was expected at
+	Line int
+}
+
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("hunk #%d of %s does not apply, expected at line %d", e.Hunk, e.File, e.Line)
+}
+
+// the maximum number of context lines, at each end of a hunk, which may be ignored when applying it
+const MaxFuzz = 2
+
+/*
+Apply applies the file diff to the contents of the original file, and returns the patched contents along
+with where each hunk was applied. Like patch(1), hunks which no longer apply at the line in their header
+are searched for elsewhere in the file (offset), and are retried ignoring up to MaxFuzz lines of leading
+and trailing context (fuzz). An *ApplyError is returned for the first hunk which can not be applied.
+
+go-gitdiff only applies hunks at the exact lines in their headers, so the hunks are located here.
+*/
+func Apply(content []byte, diff *gitdiff.File) ([]byte, []HunkResult, error) {
+	// lines keep their newline, so a last line without one only matches a hunk line without one
+	lines := splitLines(string(content))
+	var (
+		results []HunkResult
+		// difference between the patched and original line numbers from earlier hunks
+		delta int
+		// hunks may not be applied before the end of the previous hunk
+		minPos int
+	)
+	for i, fragment := range diff.TextFragments {
+		var old, new []string
+		for _, line := range fragment.Lines {
+			if line.Old() {
+				old = append(old, line.Line)
+			}
+			if line.New() {
+				new = append(new, line.Line)
+			}
+		}
+		expected := int(fragment.OldPosition) - 1 + delta
+		if fragment.OldLines == 0 {
+			// pure insertions are positioned after the line in the header
+			expected = int(fragment.OldPosition) + delta
+		}
+
+		pos, fuzz, leading, trailing := -1, 0, 0, 0
+		for fuzz = 0; fuzz <= MaxFuzz && pos < 0; fuzz++ {
+			leading = min(fuzz, int(fragment.LeadingContext))
+			trailing = min(fuzz, int(fragment.TrailingContext))
+			if fuzz > 0 && leading+trailing == 0 {
+				continue
+			}
+			pos = find(lines, old[leading:len(old)-trailing], expected+leading, minPos)
+		}
+		fuzz--
+		if pos < 0 {
+			return nil, results, &ApplyError{File: Name(diff), Hunk: i + 1, Line: int(fragment.OldPosition)}
+		}
+		pos -= leading
+
+		replacement := new[leading : len(new)-trailing]
+		patched := make([]string, 0, len(lines)-len(old)+len(new))
+		patched = append(patched, lines[:pos+leading]...)
+		patched = append(patched, replacement...)
+		patched = append(patched, lines[pos+len(old)-trailing:]...)
+		lines = patched
+
+		results = append(results, HunkResult{
+			Hunk:   i + 1,
+			Line:   pos + 1,
+			Offset: pos - expected,
+			Fuzz:   fuzz,
+		})
+		minPos = pos + leading + len(replacement)
+		delta += len(new) - len(old) + (pos - expected)
+	}
+	return []byte(strings.Join(lines, "")), results, nil
+}
+
+// find the lines in the file, searching outwards from the expected position
+func find(lines, target []string, expected, minPos int) int {
+	maxPos := len(lines) - len(target)
+	for distance := 0; ; distance++ {
+		before, after := expected-distance, expected+distance
+		if before < minPos && after > maxPos {
+			return -1
+		}
+		if after >= minPos && after <= maxPos && matchAt(lines, target, after) {
+			return after
+		}
+		if distance > 0 && before >= minPos && before <= maxPos && matchAt(lines, target, before) {
+			return before
+		}
+	}
+}
+
+func matchAt(lines, target []string, pos int) bool {
+	for i, line := range target {
+		if lines[pos+i] != line {
+			return false
+		}
+	}
+	return true
+}
+
+// split the content into lines, each keeping its trailing newline
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,153 @@
+package patch_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/pkg/patch"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func join(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func parseOne(diff string) *gitdiff.File {
+	diffs, err := patch.Parse(strings.NewReader(diff))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(diffs).To(HaveLen(1))
+	return diffs[0]
+}
+
+var _ = Describe("patch", func() {
+	var (
+		original string
+		modified string
+	)
+	BeforeEach(func() {
+		lines := numberedLines(30)
+		original = join(lines)
+		lines[4] = "changed 5"
+		lines = append(lines[:20], append([]string{"inserted"}, lines[20:]...)...)
+		lines = append(lines[:25], lines[26:]...)
+		modified = join(lines)
+	})
+
+	It("round trips a diff", func() {
+		diff := patch.Diff("api/foo.proto", "api/foo.proto", []byte(original), []byte(modified))
+		Expect(diff).To(HavePrefix("diff --git a/api/foo.proto b/api/foo.proto\n"))
+		Expect(diff).To(ContainSubstring("\n--- a/api/foo.proto\n+++ b/api/foo.proto\n@@ -2,7 +2,7 @@"))
+		fileDiff := parseOne(diff)
+		Expect(patch.Name(fileDiff)).To(Equal("api/foo.proto"))
+		Expect(fileDiff.TextFragments).To(HaveLen(2))
+
+		patched, results, err := patch.Apply([]byte(original), fileDiff)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(Equal(modified))
+		for _, result := range results {
+			Expect(result.Offset).To(Equal(0))
+			Expect(result.Fuzz).To(Equal(0))
+		}
+	})
+	It("returns an empty diff for identical content", func() {
+		Expect(patch.Diff("a", "a", []byte(original), []byte(original))).To(BeEmpty())
+	})
+	It("applies hunks at an offset", func() {
+		fileDiff := parseOne(patch.Diff("f", "f", []byte(original), []byte(modified)))
+		shifted := "new first line\nnew second line\n" + original
+		patched, results, err := patch.Apply([]byte(shifted), fileDiff)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(Equal("new first line\nnew second line\n" + modified))
+		Expect(results[0].Offset).To(Equal(2))
+		Expect(results[0].String()).To(Equal("hunk #1 succeeded at 4 (offset 2 lines)"))
+	})
+	It("applies hunks with fuzz when the surrounding context changed", func() {
+		fileDiff := parseOne(patch.Diff("f", "f", []byte(original), []byte(modified)))
+		lines := numberedLines(30)
+		lines[1] = "upstream changed line 2"
+		patched, results, err := patch.Apply([]byte(join(lines)), fileDiff)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(ContainSubstring("upstream changed line 2\nline 3\nline 4\nchanged 5\n"))
+		Expect(results[0].Fuzz).To(Equal(1))
+	})
+	It("fails clearly when a hunk no longer applies", func() {
+		fileDiff := parseOne(patch.Diff("api/foo.proto", "api/foo.proto", []byte(original), []byte(modified)))
+		lines := numberedLines(30)
+		lines[4] = "upstream changed line 5"
+		_, _, err := patch.Apply([]byte(join(lines)), fileDiff)
+		Expect(err).To(HaveOccurred())
+		var applyErr *patch.ApplyError
+		Expect(errors.As(err, &applyErr)).To(BeTrue())
+		Expect(applyErr.Hunk).To(Equal(1))
+		Expect(err.Error()).To(Equal("hunk #1 of api/foo.proto does not apply, expected at line 2"))
+	})
+	It("handles files without a trailing newline", func() {
+		old := "a\nb\nc"
+		new := "a\nb\nc\n"
+		diff := patch.Diff("f", "f", []byte(old), []byte(new))
+		Expect(diff).To(ContainSubstring("-c\n\\ No newline at end of file\n+c\n"))
+		patched, _, err := patch.Apply([]byte(old), parseOne(diff))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(Equal(new))
+
+		diff = patch.Diff("f", "f", []byte(new), []byte(old))
+		patched, _, err = patch.Apply([]byte(new), parseOne(diff))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(Equal(old))
+	})
+	It("parses diff -u and git diff output", func() {
+		diffs, err := patch.Parse(strings.NewReader(`diff --git a/validate/validate.proto b/validate/validate.proto
+index 1111111..2222222 100644
+--- a/validate/validate.proto
++++ b/validate/validate.proto
+@@ -1,3 +1,3 @@
+ syntax = "proto2";
+-package validate;
++package validate.v1;
+
+--- old/other.proto	2020-01-01 00:00:00.000000000 +0000
++++ new/other.proto	2020-01-01 00:00:00.000000000 +0000
+@@ -1 +1 @@
+-a
++b
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).To(HaveLen(2))
+		Expect(patch.Name(diffs[0])).To(Equal("validate/validate.proto"))
+		Expect(diffs[0].TextFragments[0].Lines).To(HaveLen(4))
+		Expect(diffs[1].OldName).To(Equal("new/other.proto"))
+		Expect(patch.Name(diffs[1])).To(Equal("new/other.proto"))
+	})
+	It("removes the prefixes of diff -u names", func() {
+		diffs, err := patch.Parse(strings.NewReader("--- a/api/foo.proto\n+++ b/api/foo.proto\n@@ -1 +1 @@\n-a\n+b\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch.Name(diffs[0])).To(Equal("api/foo.proto"))
+	})
+	It("creates and deletes files", func() {
+		created := parseOne(patch.Diff("/dev/null", "api/new.proto", nil, []byte("a\nb\n")))
+		Expect(created.IsNew).To(BeTrue())
+		Expect(patch.Name(created)).To(Equal("api/new.proto"))
+		patched, _, err := patch.Apply(nil, created)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patched)).To(Equal("a\nb\n"))
+
+		deleted := parseOne(patch.Diff("api/old.proto", "/dev/null", []byte("a\n"), nil))
+		Expect(deleted.IsDelete).To(BeTrue())
+		Expect(patch.Name(deleted)).To(Equal("api/old.proto"))
+	})
+	It("rejects malformed patches", func() {
+		_, err := patch.Parse(strings.NewReader("--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-zglob"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/license"
+	"github.com/solo-io/anyvendor/pkg/redact"
+)
+
+// Source is a source of vendored files, such as a go module or git repository, to check against a policy.
+type Source struct {
+	// the module path, or repository URL
+	Name string
+	// the root directory of the source, license files are read from here
+	Dir string
+	// the files vendored from the source
+	Files []string
+}
+
+// Violation is a breach of the policy by a source.
+type Violation struct {
+	Source string
+	// the vendored file which breaches the policy, empty when it applies to the whole source
+	File   string
+	Reason string
+}
+
+func (v Violation) String() string {
+	if v.File != "" {
+		return fmt.Sprintf("%s: %s: %s", v.Source, v.File, v.Reason)
+	}
+	return fmt.Sprintf("%s: %s", v.Source, v.Reason)
+}
+
+// ViolationsError is returned when one or more sources breach the policy.
+type ViolationsError struct {
+	Violations []Violation
+}
+
+func (e *ViolationsError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		lines = append(lines, "\t"+violation.String())
+	}
+	return redact.String(fmt.Sprintf("%d policy violation(s):\n%s", len(e.Violations), strings.Join(lines, "\n")))
+}
+
+/*
+Evaluate checks every source against the policy, and returns a *ViolationsError listing every violation.
+A nil policy allows everything.
+*/
+func Evaluate(policy *anyvendor.Policy, sources []Source) error {
+	if policy == nil {
+		return nil
+	}
+	var violations []Violation
+	for _, source := range sources {
+		sourceViolations, err := check(policy, source)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, sourceViolations...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Source < violations[j].Source
+	})
+	return &ViolationsError{Violations: violations}
+}
+
+func check(policy *anyvendor.Policy, source Source) ([]Violation, error) {
+	var violations []Violation
+	if len(policy.GetAllowedSources()) > 0 && !allowedSource(policy.GetAllowedSources(), source.Name) {
+		violations = append(violations, Violation{Source: source.Name, Reason: "source is not allowed"})
+	}
+
+	if len(policy.GetAllowedLicenses()) > 0 {
+		licensed, err := license.ReadSource(source.Name, "", source.Dir)
+		if err != nil {
+			return nil, err
+		}
+		licenses := licensed.Licenses()
+		if len(licenses) == 0 {
+			violations = append(violations, Violation{Source: source.Name, Reason: "license could not be detected"})
+		}
+		for _, spdx := range licenses {
+			if !allowedLicense(policy.GetAllowedLicenses(), spdx) {
+				violations = append(violations, Violation{
+					Source: source.Name,
+					Reason: fmt.Sprintf("license %s is not allowed", spdx),
+				})
+			}
+		}
+	}
+
+	for _, file := range source.Files {
+		relativePath, err := filepath.Rel(source.Dir, file)
+		if err != nil {
+			return nil, err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		for _, pattern := range policy.GetDeniedPaths() {
+			denied, err := zglob.Match(pattern, relativePath)
+			if err != nil {
+				return nil, err
+			}
+			if denied {
+				violations = append(violations, Violation{
+					Source: source.Name,
+					File:   relativePath,
+					Reason: fmt.Sprintf("path is denied by %s", pattern),
+				})
+				break
+			}
+		}
+	}
+	return violations, nil
+}
+
+// sources are matched with and without the scheme of their URL, and patterns without wildcards match as a prefix
+func allowedSource(patterns []string, name string) bool {
+	names := []string{name}
+	if i := strings.Index(name, "://"); i >= 0 {
+		names = append(names, strings.TrimSuffix(name[i+3:], ".git"))
+	}
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if name == pattern || strings.HasPrefix(name, strings.TrimSuffix(pattern, "/")+"/") {
+				return true
+			}
+			if matched, err := zglob.Match(pattern, name); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// an expression such as "MIT OR Apache-2.0" is allowed if any alternative is, and "MIT AND BSD-3-Clause" if all are
+func allowedLicense(allowed []string, spdx string) bool {
+	for _, alternative := range strings.Split(spdx, " OR ") {
+		all := true
+		for _, required := range strings.Split(alternative, " AND ") {
+			if !contains(allowed, strings.TrimSpace(required)) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/anyvendor"
+	"github.com/solo-io/anyvendor/pkg/policy"
+)
+
+var _ = Describe("Evaluate", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("SPDX-License-Identifier: MIT OR Apache-2.0\n"), 0644)).To(Succeed())
+	})
+
+	source := func(name string, files ...string) policy.Source {
+		s := policy.Source{Name: name, Dir: dir}
+		for _, file := range files {
+			s.Files = append(s.Files, filepath.Join(dir, file))
+		}
+		return s
+	}
+
+	It("allows everything without a policy", func() {
+		Expect(policy.Evaluate(nil, []policy.Source{source("github.com/foo/bar", "api/foo.proto")})).To(Succeed())
+	})
+	It("matches sources by prefix, glob, and url without its scheme", func() {
+		p := &anyvendor.Policy{AllowedSources: []string{"github.com/solo-io", "gitlab.com/*/protos"}}
+		Expect(policy.Evaluate(p, []policy.Source{
+			source("github.com/solo-io/solo-kit"),
+			source("https://gitlab.com/foo/protos.git"),
+			source("gitlab.com/foo/protos"),
+		})).To(Succeed())
+
+		err := policy.Evaluate(p, []policy.Source{source("github.com/solo-io-fork/solo-kit")})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("github.com/solo-io-fork/solo-kit: source is not allowed"))
+	})
+	It("allows license expressions with any allowed alternative", func() {
+		Expect(policy.Evaluate(&anyvendor.Policy{AllowedLicenses: []string{"MIT"}},
+			[]policy.Source{source("github.com/foo/bar")})).To(Succeed())
+
+		err := policy.Evaluate(&anyvendor.Policy{AllowedLicenses: []string{"BSD-3-Clause"}},
+			[]policy.Source{source("github.com/foo/bar")})
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*policy.ViolationsError).Violations).To(ConsistOf(policy.Violation{
+			Source: "github.com/foo/bar",
+			Reason: "license MIT OR Apache-2.0 is not allowed",
+		}))
+	})
+	It("rejects sources without a detected license", func() {
+		Expect(os.Remove(filepath.Join(dir, "LICENSE"))).To(Succeed())
+		err := policy.Evaluate(&anyvendor.Policy{AllowedLicenses: []string{"MIT"}},
+			[]policy.Source{source("github.com/foo/bar")})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("github.com/foo/bar: license could not be detected"))
+	})
+	It("reports every denied path", func() {
+		p := &anyvendor.Policy{DeniedPaths: []string{"**/internal/**", "*.pb.go"}}
+		err := policy.Evaluate(p, []policy.Source{
+			source("github.com/foo/bar", "api/foo.proto", "api/internal/secret.proto", "foo.pb.go"),
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*policy.ViolationsError).Violations).To(Equal([]policy.Violation{
+			{Source: "github.com/foo/bar", File: "api/internal/secret.proto", Reason: "path is denied by **/internal/**"},
+			{Source: "github.com/foo/bar", File: "foo.pb.go", Reason: "path is denied by *.pb.go"},
+		}))
+		Expect(err.Error()).To(HavePrefix("2 policy violation(s):\n"))
+	})
+})
@@ -0,0 +1,156 @@
+package proto
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// BreakingChangeKind is the kind of incompatible change made to a proto file.
+type BreakingChangeKind string
+
+const (
+	FileRemoved        BreakingChangeKind = "FILE_REMOVED"
+	PackageRenamed     BreakingChangeKind = "PACKAGE_RENAMED"
+	MessageRemoved     BreakingChangeKind = "MESSAGE_REMOVED"
+	FieldRemoved       BreakingChangeKind = "FIELD_REMOVED"
+	FieldNumberChanged BreakingChangeKind = "FIELD_NUMBER_CHANGED"
+	FieldTypeChanged   BreakingChangeKind = "FIELD_TYPE_CHANGED"
+)
+
+// BreakingChange is an incompatible change between two versions of a proto file.
+type BreakingChange struct {
+	Kind BreakingChangeKind
+	// the import path of the file
+	File string
+	// description of the change
+	Message string
+}
+
+func (c BreakingChange) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.File, c.Kind, c.Message)
+}
+
+// BreakingChangesError is returned when breaking changes are found, and are not allowed.
+type BreakingChangesError struct {
+	Changes []BreakingChange
+}
+
+func (e *BreakingChangesError) Error() string {
+	lines := make([]string, 0, len(e.Changes))
+	for _, change := range e.Changes {
+		lines = append(lines, "\t"+change.String())
+	}
+	return fmt.Sprintf("%d breaking change(s):\n%s", len(e.Changes), strings.Join(lines, "\n"))
+}
+
+/*
+BreakingChanges compares the previous and current versions of a set of proto files, matched by their import
+path, and returns the incompatible changes: removed files, renamed packages, removed messages and fields, and
+fields whose number or type changed. Fields are matched by name. Files which are new are not reported.
+*/
+func BreakingChanges(previous, current *descriptorpb.FileDescriptorSet) []BreakingChange {
+	currentFiles := map[string]*descriptorpb.FileDescriptorProto{}
+	for _, file := range current.GetFile() {
+		currentFiles[file.GetName()] = file
+	}
+	var changes []BreakingChange
+	for _, old := range previous.GetFile() {
+		file := fileComparison{old: old, new: currentFiles[old.GetName()]}
+		changes = append(changes, file.compare()...)
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].File < changes[j].File
+	})
+	return changes
+}
+
+type fileComparison struct {
+	old, new *descriptorpb.FileDescriptorProto
+	changes  []BreakingChange
+}
+
+func (f *fileComparison) compare() []BreakingChange {
+	if f.new == nil {
+		f.report(FileRemoved, "file was removed")
+		return f.changes
+	}
+	if f.old.GetPackage() != f.new.GetPackage() {
+		f.report(PackageRenamed, "package %q was renamed to %q", f.old.GetPackage(), f.new.GetPackage())
+	}
+	f.messages("", f.old.GetMessageType(), f.new.GetMessageType())
+	return f.changes
+}
+
+// compare the messages declared within the scope, which is the name of the enclosing message if they are nested
+func (f *fileComparison) messages(scope string, old, new []*descriptorpb.DescriptorProto) {
+	newMessages := map[string]*descriptorpb.DescriptorProto{}
+	for _, msg := range new {
+		newMessages[msg.GetName()] = msg
+	}
+	for _, oldMsg := range old {
+		name := oldMsg.GetName()
+		if scope != "" {
+			name = scope + "." + name
+		}
+		newMsg, ok := newMessages[oldMsg.GetName()]
+		if !ok {
+			f.report(MessageRemoved, "message %s was removed", name)
+			continue
+		}
+		f.fields(name, oldMsg.GetField(), newMsg.GetField())
+		f.messages(name, oldMsg.GetNestedType(), newMsg.GetNestedType())
+	}
+}
+
+func (f *fileComparison) fields(message string, old, new []*descriptorpb.FieldDescriptorProto) {
+	newFields := map[string]*descriptorpb.FieldDescriptorProto{}
+	for _, field := range new {
+		newFields[field.GetName()] = field
+	}
+	for _, oldField := range old {
+		newField, ok := newFields[oldField.GetName()]
+		if !ok {
+			f.report(FieldRemoved, "field %s.%s was removed", message, oldField.GetName())
+			continue
+		}
+		if oldField.GetNumber() != newField.GetNumber() {
+			f.report(FieldNumberChanged, "field %s.%s changed number from %d to %d", message, oldField.GetName(),
+				oldField.GetNumber(), newField.GetNumber())
+		}
+		if oldType, newType := f.fieldType(oldField, f.old.GetPackage()), f.fieldType(newField, f.new.GetPackage()); oldType != newType {
+			f.report(FieldTypeChanged, "field %s.%s changed type from %s to %s", message, oldField.GetName(),
+				oldType, newType)
+		}
+	}
+}
+
+// describe the type of the field, types from the package of the file are relative so a renamed package
+// is not also reported as a change to the type of every field which refers to it
+func (f *fileComparison) fieldType(field *descriptorpb.FieldDescriptorProto, pkg string) string {
+	var typ string
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		typ = field.GetTypeName()
+		if pkg != "" {
+			typ = strings.TrimPrefix(typ, "."+pkg+".")
+		}
+	default:
+		typ = strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+	}
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		typ = "repeated " + typ
+	}
+	return typ
+}
+
+func (f *fileComparison) report(kind BreakingChangeKind, format string, args ...interface{}) {
+	f.changes = append(f.changes, BreakingChange{
+		Kind:    kind,
+		File:    f.old.GetName(),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,110 @@
+package proto_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var _ = Describe("BreakingChanges", func() {
+	compile := func(files map[string]string) *descriptorpb.FileDescriptorSet {
+		var names []string
+		for name := range files {
+			names = append(names, name)
+		}
+		set, err := protoutils.CompileDescriptorSet(context.Background(), names, protoutils.DescriptorSetOptions{
+			IncludeRoots: []string{"/root"},
+			Accessor: func(path string) (io.ReadCloser, error) {
+				content, ok := files[filepath.ToSlash(path[len("/root/"):])]
+				if !ok {
+					return nil, os.ErrNotExist
+				}
+				return io.NopCloser(bytes.NewBufferString(content)), nil
+			},
+		})
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return set
+	}
+
+	previous := map[string]string{
+		"foo/a.proto": `syntax = "proto3";
+package foo.v1;
+message A {
+  string name = 1;
+  int32 count = 2;
+  B b = 3;
+  repeated string tags = 4;
+  message Nested {
+    string id = 1;
+  }
+}
+message B {}
+message Removed {}
+`,
+		"foo/removed.proto": `syntax = "proto3";
+package foo.v1;
+`,
+	}
+
+	It("reports no changes between identical files", func() {
+		Expect(protoutils.BreakingChanges(compile(previous), compile(previous))).To(BeEmpty())
+	})
+	It("reports compatible changes as no changes", func() {
+		Expect(protoutils.BreakingChanges(compile(previous), compile(map[string]string{
+			"foo/a.proto": `syntax = "proto3";
+package foo.v1;
+// comments and new fields are fine
+message A {
+  string name = 1;
+  int32 count = 2;
+  B b = 3;
+  repeated string tags = 4;
+  string added = 5;
+  message Nested {
+    string id = 1;
+  }
+}
+message B {}
+message Removed {}
+message Added {}
+`,
+			"foo/removed.proto": previous["foo/removed.proto"],
+			"foo/added.proto":   `syntax = "proto3";`,
+		}))).To(BeEmpty())
+	})
+	It("reports breaking changes", func() {
+		changes := protoutils.BreakingChanges(compile(previous), compile(map[string]string{
+			"foo/a.proto": `syntax = "proto3";
+package foo.v2;
+message A {
+  string name = 10;
+  int64 count = 2;
+  B b = 3;
+  string tags = 4;
+  message Nested {}
+}
+message B {}
+`,
+		}))
+		var descriptions []string
+		for _, change := range changes {
+			descriptions = append(descriptions, change.String())
+		}
+		Expect(descriptions).To(Equal([]string{
+			`foo/a.proto: PACKAGE_RENAMED: package "foo.v1" was renamed to "foo.v2"`,
+			`foo/a.proto: FIELD_NUMBER_CHANGED: field A.name changed number from 1 to 10`,
+			`foo/a.proto: FIELD_TYPE_CHANGED: field A.count changed type from int32 to int64`,
+			`foo/a.proto: FIELD_TYPE_CHANGED: field A.tags changed type from repeated string to string`,
+			`foo/a.proto: FIELD_REMOVED: field A.Nested.id was removed`,
+			`foo/a.proto: MESSAGE_REMOVED: message Removed was removed`,
+			`foo/removed.proto: FILE_REMOVED: file was removed`,
+		}))
+	})
+})
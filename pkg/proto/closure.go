@@ -0,0 +1,192 @@
+package proto
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// files shipped alongside protoc, which are skipped when ClosureOptions.SkipWellKnownImports is set
+const wellKnownImportPrefix = "google/protobuf/"
+
+// ImportSource is a directory on disk whose files can satisfy proto imports.
+type ImportSource struct {
+	// root directory of the source
+	Dir string
+	// optional import path prefix which also maps onto Dir, e.g. the go module path.
+	// This allows files to be imported both relative to the source root, and relative to the vendor dir.
+	Prefix string
+}
+
+// Resolve returns the file in fs which satisfies the import path, if this source contains one.
+func (s ImportSource) Resolve(fs afero.Fs, importPath string) (string, bool) {
+	candidates := []string{importPath}
+	if s.Prefix != "" && strings.HasPrefix(importPath, s.Prefix+"/") {
+		candidates = append(candidates, strings.TrimPrefix(importPath, s.Prefix+"/"))
+	}
+	for _, candidate := range candidates {
+		path := filepath.Join(s.Dir, filepath.FromSlash(candidate))
+		if info, err := fs.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func (s ImportSource) contains(path string) bool {
+	return strings.HasPrefix(path, filepath.Clean(s.Dir)+string(filepath.Separator))
+}
+
+// IsWellKnownImport returns true for the imports which ship with protoc, such as google/protobuf/any.proto.
+func IsWellKnownImport(importPath string) bool {
+	return strings.HasPrefix(importPath, wellKnownImportPrefix)
+}
+
+// UnresolvedImport is an import statement which could not be satisfied.
+type UnresolvedImport struct {
+	// the file containing the import statement
+	File string
+	// the line of the import statement
+	Line int
+	// the imported path
+	Import string
+	// set when the import is ambiguous, the files it matched
+	Candidates []string
+}
+
+func (u UnresolvedImport) String() string {
+	if len(u.Candidates) > 0 {
+		return fmt.Sprintf("%s:%d: %q is ambiguous, matches %s", u.File, u.Line, u.Import,
+			strings.Join(u.Candidates, ", "))
+	}
+	return fmt.Sprintf("%s:%d: %q", u.File, u.Line, u.Import)
+}
+
+// UnresolvedImportsError is returned when one or more imports could not be satisfied.
+type UnresolvedImportsError struct {
+	Imports []UnresolvedImport
+}
+
+func (e *UnresolvedImportsError) Error() string {
+	lines := make([]string, 0, len(e.Imports))
+	for _, unresolved := range e.Imports {
+		lines = append(lines, "\t"+unresolved.String())
+	}
+	return fmt.Sprintf("%d unresolved import(s):\n%s", len(e.Imports), strings.Join(lines, "\n"))
+}
+
+// ImportedFile is a file which was pulled in to satisfy an import.
+type ImportedFile struct {
+	// path of the file on disk
+	Path string
+	// index of the source, in the list passed to ImportClosure, which provided the file
+	Source int
+}
+
+// ClosureOptions configures ImportClosure.
+type ClosureOptions struct {
+	// the filesystem the files and sources are read from, the os filesystem when nil
+	Fs afero.Fs
+	/*
+		skip the well known imports, such as google/protobuf/any.proto, which are assumed to be provided by protoc,
+		listing them in the result. Otherwise they must be satisfied by the sources like any other import.
+	*/
+	SkipWellKnownImports bool
+}
+
+// ClosureResult lists the files ImportClosure pulled in.
+type ClosureResult struct {
+	Imported []ImportedFile
+	// the distinct well known imports which were skipped, sorted
+	SkippedWellKnownImports []string
+}
+
+/*
+ImportClosure follows the imports of every proto file in files, and returns the files from sources
+which are needed to satisfy them, recursively, until the closure is complete. Files already in the
+list are not returned. Imports are looked up in the source of the importing file first, and then in
+each source in order.
+
+If any imports cannot be satisfied, the files which were found are returned along with an
+*UnresolvedImportsError listing the rest.
+*/
+func ImportClosure(files []string, sources []ImportSource, opts ClosureOptions) (ClosureResult, error) {
+	fs := opts.Fs
+	if fs == nil {
+		fs = afero.NewOsFs()
+	}
+	known := make(map[string]bool, len(files))
+	queue := make([]string, 0, len(files))
+	for _, file := range files {
+		file = filepath.Clean(file)
+		if known[file] {
+			continue
+		}
+		known[file] = true
+		queue = append(queue, file)
+	}
+
+	var (
+		result     ClosureResult
+		skipped    = map[string]bool{}
+		unresolved []UnresolvedImport
+	)
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if filepath.Ext(file) != ".proto" {
+			continue
+		}
+		imports, err := parseImports(fs, file)
+		if err != nil {
+			return ClosureResult{}, err
+		}
+		for _, imp := range imports {
+			if opts.SkipWellKnownImports && IsWellKnownImport(imp.Path) {
+				if !skipped[imp.Path] {
+					skipped[imp.Path] = true
+					result.SkippedWellKnownImports = append(result.SkippedWellKnownImports, imp.Path)
+				}
+				continue
+			}
+			path, source, ok := resolveImport(fs, imp.Path, file, sources)
+			if !ok {
+				unresolved = append(unresolved, UnresolvedImport{File: file, Line: imp.Line, Import: imp.Path})
+				continue
+			}
+			if known[path] {
+				continue
+			}
+			known[path] = true
+			queue = append(queue, path)
+			result.Imported = append(result.Imported, ImportedFile{Path: path, Source: source})
+		}
+	}
+	sort.Strings(result.SkippedWellKnownImports)
+
+	if len(unresolved) > 0 {
+		return result, &UnresolvedImportsError{Imports: unresolved}
+	}
+	return result, nil
+}
+
+// find the import, preferring the source which contains the importing file
+func resolveImport(fs afero.Fs, importPath, importingFile string, sources []ImportSource) (string, int, bool) {
+	for i, source := range sources {
+		if !source.contains(importingFile) {
+			continue
+		}
+		if path, ok := source.Resolve(fs, importPath); ok {
+			return filepath.Clean(path), i, true
+		}
+	}
+	for i, source := range sources {
+		if path, ok := source.Resolve(fs, importPath); ok {
+			return filepath.Clean(path), i, true
+		}
+	}
+	return "", 0, false
+}
@@ -0,0 +1,117 @@
+package proto_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/spf13/afero"
+)
+
+func writeFile(path, content string) {
+	Expect(os.MkdirAll(filepath.Dir(path), os.ModePerm)).NotTo(HaveOccurred())
+	Expect(ioutil.WriteFile(path, []byte(content), 0644)).NotTo(HaveOccurred())
+}
+
+var _ = Describe("ImportClosure", func() {
+	var (
+		tmpDir  string
+		sources []protoutils.ImportSource
+	)
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+		sources = []protoutils.ImportSource{
+			{Dir: filepath.Join(tmpDir, "local"), Prefix: "github.com/solo-io/local"},
+			{Dir: filepath.Join(tmpDir, "validate"), Prefix: "github.com/envoyproxy/protoc-gen-validate"},
+		}
+		writeFile(filepath.Join(tmpDir, "local", "api", "a.proto"), `syntax = "proto3";
+package a;
+import "api/b.proto";
+import "validate/validate.proto";
+import "google/protobuf/any.proto";
+`)
+		writeFile(filepath.Join(tmpDir, "local", "api", "b.proto"), `syntax = "proto3";
+package b;
+import "github.com/solo-io/local/api/c.proto";
+`)
+		writeFile(filepath.Join(tmpDir, "local", "api", "c.proto"), `syntax = "proto3";
+package c;
+`)
+		writeFile(filepath.Join(tmpDir, "validate", "validate", "validate.proto"), `syntax = "proto2";
+package validate;
+import "google/protobuf/descriptor.proto";
+`)
+	})
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("follows imports across sources until the closure is complete", func() {
+		closure, err := protoutils.ImportClosure([]string{filepath.Join(tmpDir, "local", "api", "a.proto")}, sources,
+			protoutils.ClosureOptions{SkipWellKnownImports: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closure.Imported).To(ConsistOf(
+			protoutils.ImportedFile{Path: filepath.Join(tmpDir, "local", "api", "b.proto"), Source: 0},
+			protoutils.ImportedFile{Path: filepath.Join(tmpDir, "local", "api", "c.proto"), Source: 0},
+			protoutils.ImportedFile{Path: filepath.Join(tmpDir, "validate", "validate", "validate.proto"), Source: 1},
+		))
+		Expect(closure.SkippedWellKnownImports).To(Equal([]string{
+			"google/protobuf/any.proto",
+			"google/protobuf/descriptor.proto",
+		}))
+	})
+	It("resolves well known imports like any other import unless they are skipped", func() {
+		file := filepath.Join(tmpDir, "local", "api", "a.proto")
+		_, err := protoutils.ImportClosure([]string{file}, sources, protoutils.ClosureOptions{})
+		var unresolvedErr *protoutils.UnresolvedImportsError
+		Expect(errors.As(err, &unresolvedErr)).To(BeTrue())
+		Expect(unresolvedErr.Imports).To(HaveLen(2))
+
+		writeFile(filepath.Join(tmpDir, "protobuf", "google", "protobuf", "any.proto"), "syntax = \"proto3\";\n")
+		writeFile(filepath.Join(tmpDir, "protobuf", "google", "protobuf", "descriptor.proto"), "syntax = \"proto2\";\n")
+		closure, err := protoutils.ImportClosure([]string{file},
+			append(sources, protoutils.ImportSource{Dir: filepath.Join(tmpDir, "protobuf")}), protoutils.ClosureOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closure.Imported).To(ContainElements(
+			protoutils.ImportedFile{Path: filepath.Join(tmpDir, "protobuf", "google", "protobuf", "any.proto"), Source: 2},
+			protoutils.ImportedFile{Path: filepath.Join(tmpDir, "protobuf", "google", "protobuf", "descriptor.proto"), Source: 2},
+		))
+		Expect(closure.SkippedWellKnownImports).To(BeEmpty())
+	})
+	It("reads the files from the filesystem", func() {
+		fs := afero.NewMemMapFs()
+		Expect(afero.WriteFile(fs, "/mem/a.proto", []byte("syntax = \"proto3\";\nimport \"b.proto\";\n"), 0644)).To(Succeed())
+		Expect(afero.WriteFile(fs, "/mem/b.proto", []byte("syntax = \"proto3\";\n"), 0644)).To(Succeed())
+		closure, err := protoutils.ImportClosure([]string{"/mem/a.proto"}, []protoutils.ImportSource{{Dir: "/mem"}},
+			protoutils.ClosureOptions{Fs: fs})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closure.Imported).To(Equal([]protoutils.ImportedFile{{Path: "/mem/b.proto", Source: 0}}))
+	})
+	It("does not return files which were already in the list", func() {
+		closure, err := protoutils.ImportClosure([]string{
+			filepath.Join(tmpDir, "local", "api", "b.proto"),
+			filepath.Join(tmpDir, "local", "api", "c.proto"),
+		}, sources, protoutils.ClosureOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closure.Imported).To(BeEmpty())
+	})
+	It("reports imports which no source can satisfy", func() {
+		file := filepath.Join(tmpDir, "local", "api", "a.proto")
+		closure, err := protoutils.ImportClosure([]string{file}, sources[:1], protoutils.ClosureOptions{SkipWellKnownImports: true})
+		Expect(err).To(HaveOccurred())
+		Expect(closure.Imported).To(HaveLen(2))
+		var unresolvedErr *protoutils.UnresolvedImportsError
+		Expect(errors.As(err, &unresolvedErr)).To(BeTrue())
+		Expect(unresolvedErr.Imports).To(ConsistOf(protoutils.UnresolvedImport{
+			File:   file,
+			Line:   4,
+			Import: "validate/validate.proto",
+		}))
+	})
+})
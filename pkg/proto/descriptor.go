@@ -0,0 +1,171 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorSetOptions control how proto files are compiled into a FileDescriptorSet.
+type DescriptorSetOptions struct {
+	// directories searched for the files, and their imports. Well known imports (google/protobuf/*) are built in.
+	IncludeRoots []string
+	// include source code info, the locations and comments of every element, in the descriptors
+	IncludeSourceInfo bool
+	// include the descriptors of every imported file, not only the files which were compiled
+	IncludeImports bool
+	// optional function used to read the files, instead of reading them from disk. It is passed the path of
+	// the file joined to one of the include roots.
+	Accessor func(path string) (io.ReadCloser, error)
+}
+
+// CompileError is returned when proto files fail to compile, with every error reported by the compiler.
+type CompileError struct {
+	// the errors, each of which is prefixed with the file, line and column it was reported at
+	Errors []string
+}
+
+func (e *CompileError) Error() string {
+	lines := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		lines = append(lines, "\t"+err)
+	}
+	return fmt.Sprintf("%d error(s) compiling protos:\n%s", len(e.Errors), strings.Join(lines, "\n"))
+}
+
+/*
+CompileDescriptorSet compiles the proto files, which are import paths relative to one of the include roots, into a
+FileDescriptorSet. Files are ordered so every file follows its imports, as protoc does. If any file fails to
+compile, a *CompileError listing every error is returned.
+*/
+func CompileDescriptorSet(ctx context.Context, files []string, opts DescriptorSetOptions) (*descriptorpb.FileDescriptorSet, error) {
+	var (
+		mu         sync.Mutex
+		diagnostic []string
+	)
+	sourceInfo := protocompile.SourceInfoNone
+	if opts.IncludeSourceInfo {
+		sourceInfo = protocompile.SourceInfoStandard
+	}
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: opts.IncludeRoots,
+			Accessor:    opts.Accessor,
+		}),
+		SourceInfoMode: sourceInfo,
+		Reporter: reporter.NewReporter(func(err reporter.ErrorWithPos) error {
+			mu.Lock()
+			defer mu.Unlock()
+			diagnostic = append(diagnostic, err.Error())
+			// keep going, so every error is reported
+			return nil
+		}, nil),
+	}
+	compiled, err := compiler.Compile(ctx, files...)
+	if len(diagnostic) > 0 {
+		sort.Strings(diagnostic)
+		return nil, &CompileError{Errors: diagnostic}
+	}
+	if err != nil {
+		if errors.Is(err, reporter.ErrInvalidSource) {
+			return nil, &CompileError{Errors: []string{err.Error()}}
+		}
+		return nil, eris.Wrap(err, "unable to compile protos")
+	}
+
+	requested := make(map[string]bool, len(compiled))
+	for _, file := range compiled {
+		requested[file.Path()] = true
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	visited := map[string]bool{}
+	var add func(file protoreflect.FileDescriptor)
+	add = func(file protoreflect.FileDescriptor) {
+		if visited[file.Path()] {
+			return
+		}
+		visited[file.Path()] = true
+		for i := 0; i < file.Imports().Len(); i++ {
+			add(file.Imports().Get(i).FileDescriptor)
+		}
+		if requested[file.Path()] || opts.IncludeImports {
+			set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+		}
+	}
+	for _, file := range compiled {
+		add(file)
+	}
+	return set, nil
+}
+
+/*
+ImportPath returns the import path of the file, relative to the deepest include root which contains it. This
+is the path protoc would give the file were it imported relative to that root.
+*/
+func ImportPath(file string, includeRoots []string) (string, bool) {
+	var root string
+	for _, includeRoot := range includeRoots {
+		includeRoot = filepath.Clean(includeRoot)
+		if strings.HasPrefix(file, includeRoot+string(filepath.Separator)) && len(includeRoot) > len(root) {
+			root = includeRoot
+		}
+	}
+	if root == "" {
+		return "", false
+	}
+	return filepath.ToSlash(strings.TrimPrefix(file, root+string(filepath.Separator))), true
+}
+
+/*
+ImportNames returns the name each of the proto files, which are paths joined to one of the include roots, is
+compiled as: the path the other files import it by, or else its ImportPath. Naming files the way they are imported
+means a file imported by a longer path than its ImportPath is not compiled twice. An error is returned if the files
+import one of them by more than one path.
+*/
+func ImportNames(files []string, includeRoots []string) (map[string]string, error) {
+	names := map[string]string{}
+	for _, file := range files {
+		if filepath.Ext(file) != ".proto" {
+			continue
+		}
+		imports, err := ParseImports(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range imports {
+			if IsWellKnownImport(imp.Path) {
+				continue
+			}
+			matches := resolveInRoots(imp.Path, includeRoots)
+			if len(matches) != 1 {
+				continue
+			}
+			if name, ok := names[matches[0]]; ok && name != imp.Path {
+				return nil, eris.Errorf("%s is imported as both %s and %s", matches[0], name, imp.Path)
+			}
+			names[matches[0]] = imp.Path
+		}
+	}
+	for _, file := range files {
+		file = filepath.Clean(file)
+		if _, ok := names[file]; ok || filepath.Ext(file) != ".proto" {
+			continue
+		}
+		if name, ok := ImportPath(file, includeRoots); ok {
+			names[file] = name
+		}
+	}
+	return names, nil
+}
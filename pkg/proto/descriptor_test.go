@@ -0,0 +1,118 @@
+package proto_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("CompileDescriptorSet", func() {
+	var root string
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		writeFile(filepath.Join(root, "foo/a.proto"), `syntax = "proto3";
+package foo;
+
+import "foo/b.proto";
+
+// A is a message
+message A {
+  B b = 1;
+}
+`)
+		writeFile(filepath.Join(root, "foo/b.proto"), `syntax = "proto3";
+package foo;
+
+import "google/protobuf/any.proto";
+
+message B {
+  google.protobuf.Any any = 1;
+}
+`)
+	})
+
+	fileNames := func(opts protoutils.DescriptorSetOptions, files ...string) []string {
+		opts.IncludeRoots = []string{root}
+		set, err := protoutils.CompileDescriptorSet(context.Background(), files, opts)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		var names []string
+		for _, file := range set.GetFile() {
+			names = append(names, file.GetName())
+		}
+		return names
+	}
+
+	It("orders files after their imports", func() {
+		Expect(fileNames(protoutils.DescriptorSetOptions{}, "foo/a.proto", "foo/b.proto")).To(Equal([]string{"foo/b.proto", "foo/a.proto"}))
+		Expect(fileNames(protoutils.DescriptorSetOptions{}, "foo/a.proto")).To(Equal([]string{"foo/a.proto"}))
+		Expect(fileNames(protoutils.DescriptorSetOptions{IncludeImports: true}, "foo/a.proto")).To(Equal(
+			[]string{"google/protobuf/any.proto", "foo/b.proto", "foo/a.proto"}))
+	})
+	It("includes source info when requested", func() {
+		set, err := protoutils.CompileDescriptorSet(context.Background(), []string{"foo/a.proto"},
+			protoutils.DescriptorSetOptions{IncludeRoots: []string{root}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(set.GetFile()[0].GetSourceCodeInfo()).To(BeNil())
+
+		set, err = protoutils.CompileDescriptorSet(context.Background(), []string{"foo/a.proto"},
+			protoutils.DescriptorSetOptions{IncludeRoots: []string{root}, IncludeSourceInfo: true})
+		Expect(err).NotTo(HaveOccurred())
+		var comments []string
+		for _, location := range set.GetFile()[0].GetSourceCodeInfo().GetLocation() {
+			if location.GetLeadingComments() != "" {
+				comments = append(comments, location.GetLeadingComments())
+			}
+		}
+		Expect(comments).To(ContainElement(" A is a message\n"))
+	})
+	It("reports every compile error", func() {
+		writeFile(filepath.Join(root, "foo/bad.proto"), `syntax = "proto3";
+package foo;
+
+message Bad {
+  Missing missing = 1;
+  AlsoMissing also_missing = 2;
+}
+`)
+		_, err := protoutils.CompileDescriptorSet(context.Background(), []string{"foo/a.proto", "foo/bad.proto"},
+			protoutils.DescriptorSetOptions{IncludeRoots: []string{root}})
+		var compileErr *protoutils.CompileError
+		Expect(errors.As(err, &compileErr)).To(BeTrue())
+		Expect(compileErr.Errors).To(HaveLen(2))
+		Expect(compileErr.Errors[0]).To(HavePrefix("foo/bad.proto:5:3: "))
+		Expect(compileErr.Errors[1]).To(HavePrefix("foo/bad.proto:6:3: "))
+	})
+	It("returns the import path relative to the deepest include root", func() {
+		importPath, ok := protoutils.ImportPath(filepath.Join(root, "vendor", "mod", "foo", "a.proto"),
+			[]string{filepath.Join(root, "vendor"), filepath.Join(root, "vendor", "mod")})
+		Expect(ok).To(BeTrue())
+		Expect(importPath).To(Equal("foo/a.proto"))
+		_, ok = protoutils.ImportPath("/elsewhere/a.proto", []string{root})
+		Expect(ok).To(BeFalse())
+	})
+	It("names files the way they are imported", func() {
+		vendor := filepath.Join(root, "vendor")
+		mod := filepath.Join(vendor, "github.com", "foo", "mod")
+		includeRoots := []string{vendor, mod}
+		writeFile(filepath.Join(mod, "types.proto"), "syntax = \"proto3\";\npackage types;\n")
+		writeFile(filepath.Join(mod, "unused.proto"), "syntax = \"proto3\";\npackage unused;\n")
+		writeFile(filepath.Join(vendor, "app.proto"), "syntax = \"proto3\";\nimport \"github.com/foo/mod/types.proto\";\n")
+		files := []string{filepath.Join(mod, "types.proto"), filepath.Join(mod, "unused.proto"), filepath.Join(vendor, "app.proto")}
+		names, err := protoutils.ImportNames(files, includeRoots)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal(map[string]string{
+			files[0]: "github.com/foo/mod/types.proto",
+			files[1]: "unused.proto",
+			files[2]: "app.proto",
+		}))
+
+		writeFile(filepath.Join(vendor, "other.proto"), "syntax = \"proto3\";\nimport \"types.proto\";\n")
+		_, err = protoutils.ImportNames(append(files, filepath.Join(vendor, "other.proto")), includeRoots)
+		Expect(err).To(MatchError(ContainSubstring("is imported as both github.com/foo/mod/types.proto and types.proto")))
+	})
+})
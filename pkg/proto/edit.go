@@ -0,0 +1,65 @@
+package proto
+
+import (
+	"bytes"
+	"sort"
+)
+
+// a replacement of the source between the byte offsets [start, end)
+type edit struct {
+	start, end int
+	text       string
+}
+
+// apply the non overlapping edits to the source
+func applyEdits(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	var (
+		result bytes.Buffer
+		last   int
+	)
+	for _, e := range edits {
+		if e.start < last {
+			// overlaps an edit which has already been applied
+			continue
+		}
+		result.Write(src[last:e.start])
+		result.WriteString(e.text)
+		last = e.end
+	}
+	result.Write(src[last:])
+	return result.Bytes()
+}
+
+// returns an edit removing [start, end), along with the rest of the line if nothing else is on it
+func removal(src []byte, start, end int) edit {
+	lineStart := start
+	for lineStart > 0 && isBlank(src[lineStart-1]) {
+		lineStart--
+	}
+	lineEnd := end
+	for lineEnd < len(src) && isBlank(src[lineEnd]) {
+		lineEnd++
+	}
+	if (lineStart == 0 || src[lineStart-1] == '\n') && (lineEnd == len(src) || src[lineEnd] == '\n') {
+		if lineEnd < len(src) {
+			lineEnd++
+		}
+		return edit{start: lineStart, end: lineEnd}
+	}
+	return edit{start: start, end: end}
+}
+
+// returns the offset just past the end of the line containing offset
+func endOfLine(src []byte, offset int) int {
+	if i := bytes.IndexByte(src[offset:], '\n'); i >= 0 {
+		return offset + i + 1
+	}
+	return len(src)
+}
+
+func isBlank(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r'
+}
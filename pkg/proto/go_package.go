@@ -0,0 +1,102 @@
+package proto
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-zglob"
+	"github.com/rotisserie/eris"
+)
+
+// GoPackageRule sets the go_package option of the proto files matching Pattern.
+type GoPackageRule struct {
+	// glob matched against the path of the file relative to the vendor dir
+	Pattern string
+	/*
+		template for the go_package option, which may contain the following placeholders:
+			{dir}: directory of the file relative to the vendor dir
+			{module}: the module the file was vendored from
+			{file}: name of the file without its extension
+	*/
+	GoPackage string
+}
+
+// GoPackageRules is an ordered list of rules, the first rule which matches a file is applied.
+type GoPackageRules []GoPackageRule
+
+/*
+GoPackage returns the go_package for the file at path, relative to the vendor dir, which was vendored
+from module. An empty string is returned when no rule matches.
+*/
+func (r GoPackageRules) GoPackage(filePath, module string) (string, error) {
+	filePath = strings.TrimPrefix(filepath.ToSlash(filePath), "/")
+	for _, rule := range r {
+		matched, err := zglob.Match(rule.Pattern, filePath)
+		if err != nil {
+			return "", eris.Wrapf(err, "invalid go_package rule pattern %s", rule.Pattern)
+		}
+		if !matched {
+			continue
+		}
+		file := path.Base(filePath)
+		return strings.NewReplacer(
+			"{dir}", path.Dir(filePath),
+			"{module}", module,
+			"{file}", strings.TrimSuffix(file, path.Ext(file)),
+		).Replace(rule.GoPackage), nil
+	}
+	return "", nil
+}
+
+/*
+GoPackageInference derives the go_package of a vendored proto file from its path in the vendor dir,
+which starts with the module path, e.g. github.com/solo-io/solo-kit/api/v1/metadata.proto is given
+the go_package github.com/solo-io/solo-kit/api/v1.
+*/
+type GoPackageInference struct {
+	// import path the vendored directories are placed under, e.g. github.com/solo-io/my-project/pkg/api.
+	// when empty, the directory of the file is used as the import path
+	BaseImportPath string
+	// appended to the go package name, e.g. with "pb" the package name of github.com/solo-io/solo-kit/api/v1 is v1pb
+	PackageNameSuffix string
+	// replace the go_package of files which already declare one, by default those files are left untouched
+	Override bool
+}
+
+// GoPackage returns the inferred go_package for the file at filePath, relative to the vendor dir.
+func (i GoPackageInference) GoPackage(filePath string) string {
+	dir := path.Dir(strings.TrimPrefix(filepath.ToSlash(filePath), "/"))
+	importPath := dir
+	if i.BaseImportPath != "" {
+		importPath = path.Join(i.BaseImportPath, dir)
+	}
+	base := path.Base(importPath)
+	packageName := goPackageName(base) + i.PackageNameSuffix
+	if packageName == base {
+		return importPath
+	}
+	return importPath + ";" + packageName
+}
+
+// Option returns the go_package option to apply to the file at filePath, relative to the vendor dir.
+func (i GoPackageInference) Option(filePath string) FileOption {
+	option := SetOption(GoPackageOption, i.GoPackage(filePath))
+	option.KeepExisting = !i.Override
+	return option
+}
+
+// convert a path element into a valid go package name, e.g. protoc-gen-validate becomes protoc_gen_validate
+func goPackageName(element string) string {
+	name := []rune(strings.ToLower(element))
+	for idx, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			name[idx] = '_'
+		}
+	}
+	if len(name) == 0 || unicode.IsDigit(name[0]) {
+		return "_" + string(name)
+	}
+	return string(name)
+}
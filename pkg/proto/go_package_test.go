@@ -0,0 +1,66 @@
+package proto_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("GoPackageRules", func() {
+	rules := protoutils.GoPackageRules{
+		{Pattern: "github.com/solo-io/solo-kit/api/external/**/*.proto", GoPackage: "github.com/solo-io/solo-kit/pkg/api/external/{file}"},
+		{Pattern: "github.com/solo-io/solo-kit/**/*.proto", GoPackage: "{dir}"},
+		{Pattern: "**/*.proto", GoPackage: "{module}/generated"},
+	}
+
+	It("applies the first matching rule", func() {
+		goPackage, err := rules.GoPackage("github.com/solo-io/solo-kit/api/external/envoy/base.proto", "github.com/solo-io/solo-kit")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(goPackage).To(Equal("github.com/solo-io/solo-kit/pkg/api/external/base"))
+
+		goPackage, err = rules.GoPackage("github.com/solo-io/solo-kit/api/v1/metadata.proto", "github.com/solo-io/solo-kit")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(goPackage).To(Equal("github.com/solo-io/solo-kit/api/v1"))
+
+		goPackage, err = rules.GoPackage("/github.com/envoyproxy/protoc-gen-validate/validate/validate.proto", "github.com/envoyproxy/protoc-gen-validate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(goPackage).To(Equal("github.com/envoyproxy/protoc-gen-validate/generated"))
+	})
+	It("returns an empty go_package when no rule matches", func() {
+		goPackage, err := rules[:1].GoPackage("github.com/solo-io/solo-kit/api/v1/metadata.proto", "github.com/solo-io/solo-kit")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(goPackage).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GoPackageInference", func() {
+	It("derives the go_package from the vendored path", func() {
+		Expect(protoutils.GoPackageInference{}.GoPackage("/github.com/solo-io/solo-kit/api/v1/metadata.proto")).
+			To(Equal("github.com/solo-io/solo-kit/api/v1"))
+		Expect(protoutils.GoPackageInference{}.GoPackage("github.com/envoyproxy/protoc-gen-validate/validate.proto")).
+			To(Equal("github.com/envoyproxy/protoc-gen-validate;protoc_gen_validate"))
+	})
+	It("supports a base import path and package name suffix", func() {
+		inference := protoutils.GoPackageInference{
+			BaseImportPath:    "github.com/solo-io/my-project/pkg/api",
+			PackageNameSuffix: "pb",
+		}
+		Expect(inference.GoPackage("github.com/solo-io/solo-kit/api/v1/metadata.proto")).
+			To(Equal("github.com/solo-io/my-project/pkg/api/github.com/solo-io/solo-kit/api/v1;v1pb"))
+	})
+	It("only fills in missing go_package options unless told to override", func() {
+		src := []byte(`syntax = "proto3";
+package foo;
+option go_package = "github.com/upstream/foo";
+`)
+		inference := protoutils.GoPackageInference{}
+		out, err := protoutils.ApplyFileOptions("foo.proto", src, inference.Option("github.com/foo/api/foo.proto"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal(string(src)))
+
+		inference.Override = true
+		out, err = protoutils.ApplyFileOptions("foo.proto", src, inference.Option("github.com/foo/api/foo.proto"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(ContainSubstring(`option go_package = "github.com/foo/api";`))
+	})
+})
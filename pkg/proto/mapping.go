@@ -0,0 +1,41 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GoPackageMapping maps proto files, by the path they are imported with, to their go_package.
+type GoPackageMapping map[string]string
+
+func (m GoPackageMapping) files() []string {
+	files := make([]string, 0, len(m))
+	for file := range m {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// WriteMFlags writes one M<file>=<go package> line per file, these can be passed to protoc-gen-go as --go_opt values.
+func (m GoPackageMapping) WriteMFlags(w io.Writer) error {
+	for _, file := range m.files() {
+		if _, err := fmt.Fprintf(w, "M%s=%s\n", file, m[file]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBufOverrides writes a buf.gen.yaml (v2) managed mode snippet, overriding the go_package of each file.
+func (m GoPackageMapping) WriteBufOverrides(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("managed:\n  enabled: true\n  override:\n")
+	for _, file := range m.files() {
+		fmt.Fprintf(&b, "    - file_option: go_package\n      path: %s\n      value: %s\n", file, m[file])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
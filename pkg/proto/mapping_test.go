@@ -0,0 +1,38 @@
+package proto_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("GoPackageMapping", func() {
+	mapping := protoutils.GoPackageMapping{
+		"validate/validate.proto": "github.com/envoyproxy/protoc-gen-validate/validate",
+		"api/v1/metadata.proto":   "github.com/solo-io/solo-kit/pkg/api/v1;v1",
+	}
+
+	It("writes sorted M flags", func() {
+		var b bytes.Buffer
+		Expect(mapping.WriteMFlags(&b)).NotTo(HaveOccurred())
+		Expect(b.String()).To(Equal(`Mapi/v1/metadata.proto=github.com/solo-io/solo-kit/pkg/api/v1;v1
+Mvalidate/validate.proto=github.com/envoyproxy/protoc-gen-validate/validate
+`))
+	})
+	It("writes buf managed mode overrides", func() {
+		var b bytes.Buffer
+		Expect(mapping.WriteBufOverrides(&b)).NotTo(HaveOccurred())
+		Expect(b.String()).To(Equal(`managed:
+  enabled: true
+  override:
+    - file_option: go_package
+      path: api/v1/metadata.proto
+      value: github.com/solo-io/solo-kit/pkg/api/v1;v1
+    - file_option: go_package
+      path: validate/validate.proto
+      value: github.com/envoyproxy/protoc-gen-validate/validate
+`))
+	})
+})
@@ -1,11 +1,13 @@
 package proto
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/bufbuild/protocompile/ast"
 	"github.com/mattn/go-zglob"
 	"github.com/rotisserie/eris"
 )
@@ -14,6 +16,11 @@ import (
 type ProtoFilePatcher struct {
 	// specify a function that returns the go_package option the patched proto file will use
 	PatchGoPackage func(path string) string
+	// set to derive the go_package of files which do not declare one from their path, when PatchGoPackage is not specified
+	InferGoPackage *GoPackageInference
+	// specify a function that returns the file options to set, replace or remove in the patched proto file.
+	// these are applied after the go_package returned by PatchGoPackage
+	PatchFileOptions func(path string) []FileOption
 
 	// the root of the vendor dir where the proto files will be patched recursively
 	RootDir string
@@ -35,8 +42,20 @@ func (p ProtoFilePatcher) PatchProtoFiles() error {
 	}
 
 	for _, fileToPatch := range filesToPatch {
-		goPackageForFile := p.PatchGoPackage(strings.TrimPrefix(fileToPatch, p.RootDir))
-		if err := PatchProtoFile(fileToPatch, goPackageForFile); err != nil {
+		relativePath := strings.TrimPrefix(fileToPatch, p.RootDir)
+		var options []FileOption
+		switch {
+		case p.PatchGoPackage != nil:
+			if goPackageForFile := p.PatchGoPackage(relativePath); goPackageForFile != "" {
+				options = append(options, SetOption(GoPackageOption, goPackageForFile))
+			}
+		case p.InferGoPackage != nil:
+			options = append(options, p.InferGoPackage.Option(relativePath))
+		}
+		if p.PatchFileOptions != nil {
+			options = append(options, p.PatchFileOptions(relativePath)...)
+		}
+		if err := PatchFileOptions(fileToPatch, options...); err != nil {
 			return err
 		}
 	}
@@ -44,37 +63,197 @@ func (p ProtoFilePatcher) PatchProtoFiles() error {
 	return nil
 }
 
+const GoPackageOption = "go_package"
+
+// FileOption is an edit to a single file level option, such as go_package or java_package.
+type FileOption struct {
+	// name of the option as written in the file, e.g. go_package or (gogoproto.goproto_getters_all)
+	Name string
+	// value of the option as a proto literal, e.g. "github.com/solo-io/anyvendor", true or 42.
+	// Use SetOption to set a string value without quoting it yourself.
+	Value string
+	// remove the option from the file instead of setting it
+	Remove bool
+	// only set the option when the file does not already declare it
+	KeepExisting bool
+}
+
+// SetOption sets the option to a string value, replacing it if the file already declares it.
+func SetOption(name, value string) FileOption {
+	return FileOption{Name: name, Value: quote(value)}
+}
+
+// SetLiteralOption sets the option to a literal value, such as true, 42 or an enum value name.
+func SetLiteralOption(name, literal string) FileOption {
+	return FileOption{Name: name, Value: literal}
+}
+
+// RemoveOption removes the option from the file, if it is declared.
+func RemoveOption(name string) FileOption {
+	return FileOption{Name: name, Remove: true}
+}
+
+// PatchProtoFile sets the go_package option of the proto file at path. An empty goPackage leaves the file untouched.
 func PatchProtoFile(path, goPackage string) error {
 	if goPackage == "" {
 		return nil
 	}
+	return PatchFileOptions(path, SetOption(GoPackageOption, goPackage))
+}
+
+// PatchFileOptions applies the file option edits to the proto file at path.
+func PatchFileOptions(path string, options ...FileOption) error {
+	if len(options) == 0 {
+		return nil
+	}
 	b, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	lines := strings.Split(string(b), "\n")
-
-	goPackageLine := `option go_package = "` + goPackage + `";`
-	var packageDeclarationLine int
-	var replaced bool
-	for i, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "package"):
-			packageDeclarationLine = i
-		case strings.HasPrefix(line, "option go_package"):
-			// replace existing go_package
-			lines[i] = goPackageLine
-			replaced = true
-		}
+	patched, err := ApplyFileOptions(path, b, options...)
+	if err != nil {
+		return err
 	}
-	if !replaced {
-		// insert after syntax line
-		lines = append(lines[:packageDeclarationLine+1], append([]string{"\n" + goPackageLine}, lines[packageDeclarationLine+1:]...)...)
+	if bytes.Equal(b, patched) {
+		return nil
 	}
 	fileInfo, err := os.Stat(path)
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), fileInfo.Mode())
+	return ioutil.WriteFile(path, patched, fileInfo.Mode())
+}
+
+/*
+ApplyFileOptions applies the file option edits to the contents of a proto file, and returns the result.
+The filename is only used for error reporting.
+
+Existing options have their value replaced in place, and removed options have their line deleted, so
+comments and the formatting of the rest of the file are preserved. Options which are not yet declared
+are added after the last file option, or after the package declaration if there are none. When several
+edits name the same option, the last one wins.
+*/
+func ApplyFileOptions(filename string, content []byte, options ...FileOption) ([]byte, error) {
+	file, err := parseProto(filename, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		edits      []edit
+		lastOption ast.Node
+		newOptions []string
+	)
+	declared := map[string][]*ast.OptionNode{}
+	for _, decl := range file.Decls {
+		if opt, ok := decl.(*ast.OptionNode); ok {
+			name := optionName(opt.Name)
+			declared[name] = append(declared[name], opt)
+			lastOption = opt
+		}
+	}
+
+	// collapse the edits of each option, in the order the options were first named, so the last edit wins
+	var (
+		names     []string
+		collapsed = map[string]FileOption{}
+	)
+	for _, option := range options {
+		name := strings.Join(strings.Fields(option.Name), "")
+		previous, ok := collapsed[name]
+		if !ok {
+			names = append(names, name)
+		}
+		if option.KeepExisting {
+			// an earlier edit which set the option declares it, and an earlier removal undeclares it
+			if ok && !previous.Remove {
+				continue
+			}
+			option.KeepExisting = option.KeepExisting && !ok
+		}
+		collapsed[name] = option
+	}
+
+	for _, name := range names {
+		option := collapsed[name]
+		existing := declared[name]
+		switch {
+		case option.Remove:
+			for _, opt := range existing {
+				start, end := span(file, opt)
+				edits = append(edits, removal(content, start, end))
+			}
+		case len(existing) > 0 && option.KeepExisting:
+			continue
+		case len(existing) > 0:
+			for _, opt := range existing {
+				start, end := span(file, opt.Val)
+				edits = append(edits, edit{start: start, end: end, text: option.Value})
+			}
+		default:
+			newOptions = append(newOptions, "option "+option.Name+" = "+option.Value+";\n")
+		}
+	}
+
+	if len(newOptions) > 0 {
+		insertion := strings.Join(newOptions, "")
+		var anchor ast.Node
+		switch {
+		case lastOption != nil:
+			anchor = lastOption
+		case packageNode(file) != nil:
+			anchor = packageNode(file)
+			insertion = "\n" + insertion
+		case file.Syntax != nil:
+			anchor = file.Syntax
+			insertion = "\n" + insertion
+		case file.Edition != nil:
+			anchor = file.Edition
+			insertion = "\n" + insertion
+		}
+		offset := 0
+		if anchor != nil {
+			_, end := span(file, anchor)
+			offset = endOfLine(content, end)
+			if offset == len(content) && (offset == 0 || content[offset-1] != '\n') {
+				insertion = "\n" + insertion
+			}
+		}
+		edits = append(edits, edit{start: offset, end: offset, text: insertion})
+	}
+
+	return applyEdits(content, edits), nil
+}
+
+func packageNode(file *ast.FileNode) *ast.PackageNode {
+	for _, decl := range file.Decls {
+		if pkg, ok := decl.(*ast.PackageNode); ok {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// quote a string as a proto string literal
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
 }
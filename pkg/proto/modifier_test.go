@@ -0,0 +1,175 @@
+package proto_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("file options", func() {
+	apply := func(src string, options ...protoutils.FileOption) string {
+		out, err := protoutils.ApplyFileOptions("test.proto", []byte(src), options...)
+		Expect(err).NotTo(HaveOccurred())
+		return string(out)
+	}
+
+	It("replaces an existing option in place, preserving comments", func() {
+		src := `syntax = "proto3";
+// the package for this file
+package foo.bar;
+
+  option go_package = "github.com/old/pkg"; // keep me
+option java_package = "com.foo";
+`
+		Expect(apply(src, protoutils.SetOption("go_package", "github.com/new/pkg"))).To(Equal(`syntax = "proto3";
+// the package for this file
+package foo.bar;
+
+  option go_package = "github.com/new/pkg"; // keep me
+option java_package = "com.foo";
+`))
+	})
+	It("adds missing options after the package declaration, ignoring package in comments", func() {
+		src := `// this package is great
+syntax = "proto3";
+/* package not.this; */
+package foo.bar;
+
+message Foo {}
+`
+		Expect(apply(src,
+			protoutils.SetOption("go_package", "github.com/foo/bar"),
+			protoutils.SetOption("csharp_namespace", "Foo.Bar"),
+		)).To(Equal(`// this package is great
+syntax = "proto3";
+/* package not.this; */
+package foo.bar;
+
+option go_package = "github.com/foo/bar";
+option csharp_namespace = "Foo.Bar";
+
+message Foo {}
+`))
+	})
+	It("adds missing options after the last existing option", func() {
+		src := `syntax = "proto3";
+package foo;
+option java_package = "com.foo";
+message Foo {}
+`
+		Expect(apply(src, protoutils.SetOption("ruby_package", "Foo"))).To(Equal(`syntax = "proto3";
+package foo;
+option java_package = "com.foo";
+option ruby_package = "Foo";
+message Foo {}
+`))
+	})
+	It("can set and remove custom and literal options", func() {
+		src := `syntax = "proto3";
+package foo;
+option (gogoproto.goproto_getters_all) = false;
+option objc_class_prefix = "FOO";
+option cc_enable_arenas = false;
+`
+		Expect(apply(src,
+			protoutils.RemoveOption("( gogoproto.goproto_getters_all )"),
+			protoutils.RemoveOption("objc_class_prefix"),
+			protoutils.SetLiteralOption("cc_enable_arenas", "true"),
+		)).To(Equal(`syntax = "proto3";
+package foo;
+option cc_enable_arenas = true;
+`))
+	})
+	It("does not touch options nested in messages", func() {
+		src := `syntax = "proto3";
+package foo;
+message Foo {
+  option deprecated = true;
+}
+`
+		Expect(apply(src, protoutils.RemoveOption("deprecated"))).To(Equal(src))
+	})
+	It("applies the last edit of an option which is not declared", func() {
+		src := `syntax = "proto3";
+package foo;
+`
+		Expect(apply(src,
+			protoutils.SetOption("go_package", "github.com/first/pkg"),
+			protoutils.SetOption("java_package", "com.foo"),
+			protoutils.SetOption("go_package", "github.com/last/pkg"),
+			protoutils.FileOption{Name: "go_package", Value: `"github.com/kept/pkg"`, KeepExisting: true},
+		)).To(Equal(`syntax = "proto3";
+package foo;
+
+option go_package = "github.com/last/pkg";
+option java_package = "com.foo";
+`))
+	})
+	It("applies the last edit of an option which is declared", func() {
+		src := `syntax = "proto3";
+package foo;
+option go_package = "github.com/old/pkg";
+option java_package = "com.foo";
+`
+		Expect(apply(src,
+			protoutils.SetOption("go_package", "github.com/first/pkg"),
+			protoutils.SetOption("go_package", "github.com/last/pkg"),
+			protoutils.RemoveOption("java_package"),
+			protoutils.SetOption("java_package", "com.bar"),
+		)).To(Equal(`syntax = "proto3";
+package foo;
+option go_package = "github.com/last/pkg";
+option java_package = "com.bar";
+`))
+		Expect(apply(src,
+			protoutils.SetOption("go_package", "github.com/new/pkg"),
+			protoutils.RemoveOption("go_package"),
+		)).To(Equal(`syntax = "proto3";
+package foo;
+option java_package = "com.foo";
+`))
+	})
+	It("errors on invalid protos", func() {
+		_, err := protoutils.ApplyFileOptions("test.proto", []byte(`package foo`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("ProtoFilePatcher", func() {
+		var tmpDir string
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+			writeFile(filepath.Join(tmpDir, "api", "foo.proto"), `syntax = "proto3";
+package foo;
+option java_package = "com.foo";
+`)
+		})
+		AfterEach(func() {
+			_ = os.RemoveAll(tmpDir)
+		})
+		It("patches go_package and other file options", func() {
+			err := protoutils.ProtoFilePatcher{
+				PatchGoPackage: func(path string) string {
+					return filepath.Join("github.com/foo", filepath.Dir(path))
+				},
+				PatchFileOptions: func(path string) []protoutils.FileOption {
+					return []protoutils.FileOption{protoutils.RemoveOption("java_package")}
+				},
+				RootDir:       tmpDir,
+				MatchPatterns: []string{"**/*.proto"},
+			}.PatchProtoFiles()
+			Expect(err).NotTo(HaveOccurred())
+			b, err := ioutil.ReadFile(filepath.Join(tmpDir, "api", "foo.proto"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal(`syntax = "proto3";
+package foo;
+option go_package = "github.com/foo/api";
+`))
+		})
+	})
+})
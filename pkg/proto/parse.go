@@ -0,0 +1,76 @@
+package proto
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/bufbuild/protocompile/ast"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/rotisserie/eris"
+	"github.com/spf13/afero"
+)
+
+// Import is a single import statement found in a proto file.
+type Import struct {
+	// the imported path, as written in the file
+	Path string
+	// the line (1 based) the import statement starts on
+	Line int
+}
+
+// ParseImports returns all of the import statements declared in the proto file at path.
+func ParseImports(path string) ([]Import, error) {
+	return parseImports(afero.NewOsFs(), path)
+}
+
+func parseImports(fs afero.Fs, path string) ([]Import, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parseProto(path, b)
+	if err != nil {
+		return nil, err
+	}
+	var imports []Import
+	for _, decl := range file.Decls {
+		imp, ok := decl.(*ast.ImportNode)
+		if !ok {
+			continue
+		}
+		imports = append(imports, Import{
+			Path: imp.Name.AsString(),
+			Line: file.NodeInfo(imp).Start().Line,
+		})
+	}
+	return imports, nil
+}
+
+// parse the contents of a proto file into an AST, the filename is only used for error reporting.
+func parseProto(filename string, content []byte) (*ast.FileNode, error) {
+	file, err := parser.Parse(filename, bytes.NewReader(content), reporter.NewHandler(nil))
+	if err != nil {
+		return nil, eris.Wrapf(err, "unable to parse proto file %s", filename)
+	}
+	return file, nil
+}
+
+// returns the byte offsets [start, end) of the node in the source
+func span(file *ast.FileNode, node ast.Node) (int, int) {
+	info := file.NodeInfo(node)
+	return info.Start().Offset, info.End().Offset + 1
+}
+
+// returns the option name as written in the file, without whitespace, e.g. (gogoproto.goproto_getters_all)
+func optionName(name *ast.OptionNameNode) string {
+	parts := make([]string, 0, len(name.Parts))
+	for _, part := range name.Parts {
+		ident := string(part.Name.AsIdentifier())
+		if part.IsExtension() {
+			ident = "(" + ident + ")"
+		}
+		parts = append(parts, ident)
+	}
+	return strings.Join(parts, ".")
+}
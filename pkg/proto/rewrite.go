@@ -0,0 +1,104 @@
+package proto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/bufbuild/protocompile/ast"
+)
+
+/*
+ImportMapping maps the paths of relocated proto files from their old path to their new one, so the import
+statements which refer to them can be rewritten. A key ending in "/" is a directory, and maps every import
+beneath it, e.g. {"validate/": "github.com/envoyproxy/protoc-gen-validate/validate/"}.
+
+ImportMapping implements transform.Transformer, so it can be run on files as they are vendored.
+*/
+type ImportMapping map[string]string
+
+// Rewrite returns the new path of the import. Exact mappings take precedence over directories, and longer
+// directories over shorter ones.
+func (m ImportMapping) Rewrite(importPath string) (string, bool) {
+	if rewritten, ok := m[importPath]; ok {
+		return rewritten, true
+	}
+	var longest string
+	for from := range m {
+		if strings.HasSuffix(from, "/") && strings.HasPrefix(importPath, from) && len(from) > len(longest) {
+			longest = from
+		}
+	}
+	if longest == "" {
+		return "", false
+	}
+	return m[longest] + strings.TrimPrefix(importPath, longest), true
+}
+
+/*
+RewriteImports rewrites the import statements in the contents of a proto file, and returns the result. Only the
+imported path is replaced, so comments and the formatting of the rest of the file are preserved.
+The filename is only used for error reporting.
+*/
+func (m ImportMapping) RewriteImports(filename string, content []byte) ([]byte, error) {
+	file, err := parseProto(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	var edits []edit
+	for _, decl := range file.Decls {
+		imp, ok := decl.(*ast.ImportNode)
+		if !ok {
+			continue
+		}
+		rewritten, ok := m.Rewrite(imp.Name.AsString())
+		if !ok || rewritten == imp.Name.AsString() {
+			continue
+		}
+		start, end := span(file, imp.Name)
+		edits = append(edits, edit{start: start, end: end, text: quote(rewritten)})
+	}
+	return applyEdits(content, edits), nil
+}
+
+// RewriteFiles rewrites the import statements of every proto file in files, other files are skipped.
+func (m ImportMapping) RewriteFiles(files []string) error {
+	for _, file := range files {
+		if path.Ext(file) != ".proto" {
+			continue
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rewritten, err := m.RewriteImports(file, b)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(b, rewritten) {
+			continue
+		}
+		fileInfo, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file, rewritten, fileInfo.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Transform rewrites the import statements of proto files, other files are returned untouched.
+func (m ImportMapping) Transform(filePath string, content []byte) (string, []byte, error) {
+	if path.Ext(filePath) != ".proto" {
+		return filePath, content, nil
+	}
+	rewritten, err := m.RewriteImports(filePath, content)
+	if err != nil {
+		return "", nil, err
+	}
+	return filePath, rewritten, nil
+}
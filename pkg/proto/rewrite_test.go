@@ -0,0 +1,65 @@
+package proto_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("ImportMapping", func() {
+	mapping := protoutils.ImportMapping{
+		"validate/":                   "github.com/envoyproxy/protoc-gen-validate/validate/",
+		"validate/legacy/":            "legacy/",
+		"gogoproto/gogo.proto":        "github.com/gogo/protobuf/gogoproto/gogo.proto",
+		"google/protobuf/empty.proto": "google/protobuf/empty.proto",
+	}
+
+	It("rewrites exact and directory mappings", func() {
+		rewritten, ok := mapping.Rewrite("validate/validate.proto")
+		Expect(ok).To(BeTrue())
+		Expect(rewritten).To(Equal("github.com/envoyproxy/protoc-gen-validate/validate/validate.proto"))
+
+		rewritten, ok = mapping.Rewrite("validate/legacy/v1.proto")
+		Expect(ok).To(BeTrue())
+		Expect(rewritten).To(Equal("legacy/v1.proto"))
+
+		rewritten, ok = mapping.Rewrite("gogoproto/gogo.proto")
+		Expect(ok).To(BeTrue())
+		Expect(rewritten).To(Equal("github.com/gogo/protobuf/gogoproto/gogo.proto"))
+
+		_, ok = mapping.Rewrite("foo/validate/validate.proto")
+		Expect(ok).To(BeFalse())
+	})
+	It("rewrites the import statements of files", func() {
+		dir := GinkgoT().TempDir()
+		file := filepath.Join(dir, "foo.proto")
+		Expect(os.WriteFile(file, []byte(`syntax = "proto3";
+package foo;
+
+// validation
+import "validate/validate.proto";
+import public "gogoproto/gogo.proto";
+import "google/protobuf/empty.proto";
+`), 0644)).NotTo(HaveOccurred())
+		other := filepath.Join(dir, "README.md")
+		Expect(os.WriteFile(other, []byte(`import "validate/validate.proto";`), 0644)).NotTo(HaveOccurred())
+
+		Expect(mapping.RewriteFiles([]string{file, other})).NotTo(HaveOccurred())
+		b, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`syntax = "proto3";
+package foo;
+
+// validation
+import "github.com/envoyproxy/protoc-gen-validate/validate/validate.proto";
+import public "github.com/gogo/protobuf/gogoproto/gogo.proto";
+import "google/protobuf/empty.proto";
+`))
+		b, err = os.ReadFile(other)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`import "validate/validate.proto";`))
+	})
+})
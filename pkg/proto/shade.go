@@ -0,0 +1,456 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/ast"
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+/*
+Shader relocates a set of proto files under an alternate import path and proto package, so they can be compiled
+alongside another copy of the same files, such as v1 and v2 of the same upstream API.
+
+The package declaration of every shaded file is prefixed with PackagePrefix, and its import path with
+PathPrefix. Imports of the shaded files, and references to the types they declare, are rewritten to match.
+Every type reference in a shaded file is written fully qualified, as relative references may resolve
+differently once the package has changed. Extensions of messages which are not shaded, such as custom options
+extending google.protobuf.FieldOptions, must keep their field numbers, which are part of the wire format, so they
+are listed in the result, and FindExtensionConflicts finds the files they can not be compiled with.
+*/
+type Shader struct {
+	// prefix added to the proto package of the shaded files, e.g. v1 shades envoy.api.v2 as v1.envoy.api.v2
+	PackagePrefix string
+	// prefix added to the import path of the shaded files, e.g. v1 shades envoy/api/v2/cds.proto as v1/envoy/api/v2/cds.proto
+	PathPrefix string
+	// directories searched for the imports of the shaded files which are not shaded themselves.
+	// Well known imports (google/protobuf/*) are built in.
+	IncludeRoots []string
+	// opens the files found in root and the include roots, by default they are read from disk
+	Accessor func(path string) (io.ReadCloser, error)
+}
+
+// ShadedFile is the shaded contents of a proto file.
+type ShadedFile struct {
+	// the import path the file had before it was shaded
+	OriginalPath string
+	// the import path of the shaded file
+	Path    string
+	Content []byte
+	// the extensions the file declares of messages which are not shaded
+	Extensions []Extension
+}
+
+// Extension is a field declared by a shaded file, extending a message which is not shaded.
+type Extension struct {
+	// the import path of the shaded file
+	File string
+	// the shaded full name of the extension
+	Name string
+	// the full name of the extended message
+	Extendee string
+	Number   int32
+}
+
+/*
+Shade compiles the files, which are import paths relative to root, and returns their shaded contents in the same
+order. The files must import each other relative to root.
+*/
+func (s Shader) Shade(ctx context.Context, root string, files []string) ([]ShadedFile, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: append([]string{root}, s.IncludeRoots...),
+			Accessor:    s.Accessor,
+		}),
+		RetainASTs: true,
+	}
+	compiled, err := compiler.Compile(ctx, files...)
+	if err != nil {
+		return nil, eris.Wrapf(err, "unable to compile the files to shade in %s", root)
+	}
+
+	shadedFiles := make(map[string]bool, len(files))
+	shadedSymbols := map[string]bool{}
+	for _, file := range compiled {
+		shadedFiles[file.Path()] = true
+		collectSymbols(file, shadedSymbols)
+	}
+	allSymbols := map[string]bool{}
+	visited := map[string]bool{}
+	for _, file := range compiled {
+		collectImportedSymbols(file, allSymbols, visited)
+	}
+
+	result := make([]ShadedFile, 0, len(compiled))
+	for _, file := range compiled {
+		res, ok := file.(linker.Result)
+		if !ok {
+			return nil, eris.Errorf("%s was not compiled from source", file.Path())
+		}
+		// the shaded files are always found in root, as it is searched first
+		content, err := s.readFile(filepath.Join(root, filepath.FromSlash(file.Path())))
+		if err != nil {
+			return nil, err
+		}
+		shading := &shading{
+			Shader:        s,
+			result:        res,
+			shadedFiles:   shadedFiles,
+			shadedSymbols: shadedSymbols,
+			allSymbols:    allSymbols,
+			edited:        map[ast.Node]bool{},
+		}
+		content, err = shading.shade(content)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ShadedFile{
+			OriginalPath: file.Path(),
+			Path:         s.shadedPath(file.Path()),
+			Content:      content,
+			Extensions:   s.extensions(file, shadedSymbols),
+		})
+	}
+	return result, nil
+}
+
+// returns the extensions the file declares of messages which are not shaded
+func (s Shader) extensions(file protoreflect.FileDescriptor, shadedSymbols map[string]bool) []Extension {
+	var (
+		result     []Extension
+		messages   func(protoreflect.MessageDescriptors)
+		extensions = func(descriptors protoreflect.ExtensionDescriptors) {
+			for i := 0; i < descriptors.Len(); i++ {
+				ext := descriptors.Get(i)
+				extendee := string(ext.ContainingMessage().FullName())
+				if shadedSymbols[extendee] {
+					continue
+				}
+				result = append(result, Extension{
+					File:     s.shadedPath(file.Path()),
+					Name:     s.shadedName(string(ext.FullName())),
+					Extendee: extendee,
+					Number:   int32(ext.Number()),
+				})
+			}
+		}
+	)
+	messages = func(descriptors protoreflect.MessageDescriptors) {
+		for i := 0; i < descriptors.Len(); i++ {
+			extensions(descriptors.Get(i).Extensions())
+			messages(descriptors.Get(i).Messages())
+		}
+	}
+	extensions(file.Extensions())
+	messages(file.Messages())
+	return result
+}
+
+// ExtensionConflict is an extension of a shaded file, which has the same number as an extension of the same message
+// declared by another file.
+type ExtensionConflict struct {
+	Extension Extension
+	// the file declaring the other extension, and its name as written
+	File string
+	Name string
+}
+
+func (c ExtensionConflict) String() string {
+	return fmt.Sprintf("%s: %s extends %s with number %d, which %s already uses for %s", c.Extension.File,
+		c.Extension.Name, c.Extension.Extendee, c.Extension.Number, c.File, c.Name)
+}
+
+// ExtensionConflictsError is returned when shaded extensions conflict with the extensions of other files.
+type ExtensionConflictsError struct {
+	Conflicts []ExtensionConflict
+}
+
+func (e *ExtensionConflictsError) Error() string {
+	lines := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		lines = append(lines, "\t"+conflict.String())
+	}
+	return fmt.Sprintf("%d shaded extension(s) keep the number of an extension which is not shaded, so the files "+
+		"can not be compiled together:\n%s", len(e.Conflicts), strings.Join(lines, "\n"))
+}
+
+/*
+FindExtensionConflicts returns the shaded extensions which have the same number as an extension of the same message
+declared by the proto file, which is not shaded. The extendees of the file are resolved by name only, so an extendee
+written relative to the package of the file matches any message whose full name ends with it.
+*/
+func FindExtensionConflicts(extensions []Extension, filename string, content []byte) ([]ExtensionConflict, error) {
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+	file, err := parseProto(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []ExtensionConflict
+	err = ast.Walk(file, ast.NoOpVisitor{}, ast.WithBefore(func(node ast.Node) error {
+		extend, ok := node.(*ast.ExtendNode)
+		if !ok {
+			return nil
+		}
+		extendee := strings.TrimPrefix(string(extend.Extendee.AsIdentifier()), ".")
+		for _, decl := range extend.Decls {
+			var (
+				name string
+				tag  *ast.UintLiteralNode
+			)
+			switch field := decl.(type) {
+			case *ast.FieldNode:
+				name, tag = field.Name.Val, field.Tag
+			case *ast.GroupNode:
+				name, tag = field.Name.Val, field.Tag
+			default:
+				continue
+			}
+			if tag == nil {
+				continue
+			}
+			for _, ext := range extensions {
+				if uint64(ext.Number) == tag.Val &&
+					(ext.Extendee == extendee || strings.HasSuffix(ext.Extendee, "."+extendee)) {
+					conflicts = append(conflicts, ExtensionConflict{Extension: ext, File: filename, Name: name})
+				}
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+func (s Shader) readFile(path string) ([]byte, error) {
+	if s.Accessor == nil {
+		return ioutil.ReadFile(path)
+	}
+	f, err := s.Accessor(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+func (s Shader) shadedPath(importPath string) string {
+	return path.Join(s.PathPrefix, importPath)
+}
+
+func (s Shader) shadedName(fullName string) string {
+	if fullName == "" {
+		return s.PackagePrefix
+	}
+	return s.PackagePrefix + "." + fullName
+}
+
+// the shading of a single compiled file
+type shading struct {
+	Shader
+	result        linker.Result
+	shadedFiles   map[string]bool
+	shadedSymbols map[string]bool
+	allSymbols    map[string]bool
+	edits         []edit
+	edited        map[ast.Node]bool
+}
+
+func (s *shading) shade(content []byte) ([]byte, error) {
+	file := s.result.AST()
+	fd := s.result.FileDescriptorProto()
+
+	var pkg *ast.PackageNode
+	for _, decl := range file.Decls {
+		switch n := decl.(type) {
+		case *ast.PackageNode:
+			pkg = n
+			s.replace(n.Name, s.shadedName(fd.GetPackage()))
+		case *ast.ImportNode:
+			if s.shadedFiles[n.Name.AsString()] {
+				s.replace(n.Name, quote(s.shadedPath(n.Name.AsString())))
+			}
+		}
+	}
+	if pkg == nil {
+		// files without a package are given one, after the syntax declaration
+		insertion := "\npackage " + s.PackagePrefix + ";\n"
+		offset := 0
+		if file.Syntax != nil {
+			_, end := span(file, file.Syntax)
+			offset = end
+		} else if file.Edition != nil {
+			_, end := span(file, file.Edition)
+			offset = end
+		}
+		s.edits = append(s.edits, edit{start: offset, end: offset, text: insertion})
+	}
+
+	for _, field := range fd.GetExtension() {
+		s.field(field)
+	}
+	for _, msg := range fd.GetMessageType() {
+		s.message(msg)
+	}
+	for _, svc := range fd.GetService() {
+		for _, method := range svc.GetMethod() {
+			if rpc, ok := s.result.MethodNode(method).(*ast.RPCNode); ok {
+				s.reference(rpc.Input.MessageType, method.GetInputType())
+				s.reference(rpc.Output.MessageType, method.GetOutputType())
+			}
+		}
+	}
+
+	scope := fd.GetPackage()
+	err := ast.Walk(file, ast.NoOpVisitor{}, ast.WithBefore(func(node ast.Node) error {
+		if opt, ok := node.(*ast.OptionNode); ok {
+			s.optionName(opt, scope)
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return applyEdits(content, s.edits), nil
+}
+
+func (s *shading) message(msg *descriptorpb.DescriptorProto) {
+	for _, field := range msg.GetField() {
+		s.field(field)
+	}
+	for _, field := range msg.GetExtension() {
+		s.field(field)
+	}
+	for _, nested := range msg.GetNestedType() {
+		s.message(nested)
+	}
+}
+
+func (s *shading) field(field *descriptorpb.FieldDescriptorProto) {
+	node := s.result.FieldNode(field)
+	if ident, ok := node.FieldType().(ast.IdentValueNode); ok && field.GetTypeName() != "" {
+		s.reference(ident, field.GetTypeName())
+	}
+	if fieldNode, ok := node.(*ast.FieldNode); ok && fieldNode.Extendee != nil {
+		s.reference(fieldNode.Extendee.Extendee, field.GetExtendee())
+	}
+}
+
+// rewrite the reference to the fully qualified name, shading it if it is declared by a shaded file
+func (s *shading) reference(node ast.IdentValueNode, fullName string) {
+	name := strings.TrimPrefix(fullName, ".")
+	if name == "" {
+		return
+	}
+	if s.shadedSymbols[name] {
+		name = s.shadedName(name)
+	}
+	s.replace(node, "."+name)
+}
+
+// rewrite the extension names of the option, e.g. (foo.bar), which do not resolve once the package is shaded
+func (s *shading) optionName(opt *ast.OptionNode, scope string) {
+	for _, part := range opt.Name.Parts {
+		if !part.IsExtension() {
+			continue
+		}
+		name := string(part.Name.AsIdentifier())
+		fullName, ok := s.resolve(name, scope)
+		if !ok {
+			continue
+		}
+		s.reference(part.Name, fullName)
+	}
+}
+
+// resolve a name relative to the scope, following the proto scoping rules
+func (s *shading) resolve(name, scope string) (string, bool) {
+	if strings.HasPrefix(name, ".") {
+		return name, s.allSymbols[name[1:]]
+	}
+	for {
+		candidate := name
+		if scope != "" {
+			candidate = scope + "." + name
+		}
+		if s.allSymbols[candidate] {
+			return candidate, true
+		}
+		if scope == "" {
+			return "", false
+		}
+		if i := strings.LastIndexByte(scope, '.'); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+func (s *shading) replace(node ast.Node, text string) {
+	if s.edited[node] {
+		return
+	}
+	s.edited[node] = true
+	if _, ok := node.(*ast.NoSourceNode); ok {
+		return
+	}
+	start, end := span(s.result.AST(), node)
+	s.edits = append(s.edits, edit{start: start, end: end, text: text})
+}
+
+// add the fully qualified names of every symbol declared by the file
+func collectSymbols(file protoreflect.FileDescriptor, symbols map[string]bool) {
+	var messages func(protoreflect.MessageDescriptors)
+	enums := func(descriptors protoreflect.EnumDescriptors) {
+		for i := 0; i < descriptors.Len(); i++ {
+			symbols[string(descriptors.Get(i).FullName())] = true
+		}
+	}
+	extensions := func(descriptors protoreflect.ExtensionDescriptors) {
+		for i := 0; i < descriptors.Len(); i++ {
+			symbols[string(descriptors.Get(i).FullName())] = true
+		}
+	}
+	messages = func(descriptors protoreflect.MessageDescriptors) {
+		for i := 0; i < descriptors.Len(); i++ {
+			msg := descriptors.Get(i)
+			symbols[string(msg.FullName())] = true
+			messages(msg.Messages())
+			enums(msg.Enums())
+			extensions(msg.Extensions())
+		}
+	}
+	messages(file.Messages())
+	enums(file.Enums())
+	extensions(file.Extensions())
+	for i := 0; i < file.Services().Len(); i++ {
+		symbols[string(file.Services().Get(i).FullName())] = true
+	}
+}
+
+// add the symbols of the file, and of every file it imports
+func collectImportedSymbols(file protoreflect.FileDescriptor, symbols map[string]bool, visited map[string]bool) {
+	if visited[file.Path()] {
+		return
+	}
+	visited[file.Path()] = true
+	collectSymbols(file, symbols)
+	for i := 0; i < file.Imports().Len(); i++ {
+		collectImportedSymbols(file.Imports().Get(i).FileDescriptor, symbols, visited)
+	}
+}
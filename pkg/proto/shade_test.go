@@ -0,0 +1,186 @@
+package proto_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/protocompile"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("Shader", func() {
+	var root, includeRoot string
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		includeRoot = GinkgoT().TempDir()
+		writeFile(filepath.Join(root, "foo/a/a.proto"), `syntax = "proto2";
+package foo.a;
+
+import "foo/b/b.proto";
+import "ext/ext.proto";
+import "google/protobuf/any.proto";
+
+message A {
+  optional b.B b = 1;
+  optional .foo.b.B c = 2;
+  optional ext.E e = 3;
+  optional google.protobuf.Any any = 4;
+  map<string, b.B> bs = 5;
+  optional Nested nested = 6;
+  message Nested {}
+}
+
+extend b.B {
+  optional string note = 100;
+}
+
+service Service {
+  rpc Get(A) returns (foo.b.B);
+}
+`)
+		writeFile(filepath.Join(root, "foo/b/b.proto"), `syntax = "proto2";
+package foo.b;
+
+message B {
+  extensions 100 to 200;
+  optional string name = 1;
+}
+`)
+		writeFile(filepath.Join(includeRoot, "ext/ext.proto"), `syntax = "proto2";
+package ext;
+message E {}
+`)
+	})
+
+	It("shades the package, imports and type references", func() {
+		shader := protoutils.Shader{PackagePrefix: "v1", PathPrefix: "v1", IncludeRoots: []string{includeRoot}}
+		shaded, err := shader.Shade(context.Background(), root, []string{"foo/a/a.proto", "foo/b/b.proto"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shaded).To(HaveLen(2))
+		Expect(shaded[0].OriginalPath).To(Equal("foo/a/a.proto"))
+		Expect(shaded[0].Path).To(Equal("v1/foo/a/a.proto"))
+		Expect(string(shaded[0].Content)).To(Equal(`syntax = "proto2";
+package v1.foo.a;
+
+import "v1/foo/b/b.proto";
+import "ext/ext.proto";
+import "google/protobuf/any.proto";
+
+message A {
+  optional .v1.foo.b.B b = 1;
+  optional .v1.foo.b.B c = 2;
+  optional .ext.E e = 3;
+  optional .google.protobuf.Any any = 4;
+  map<string, .v1.foo.b.B> bs = 5;
+  optional .v1.foo.a.A.Nested nested = 6;
+  message Nested {}
+}
+
+extend .v1.foo.b.B {
+  optional string note = 100;
+}
+
+service Service {
+  rpc Get(.v1.foo.a.A) returns (.v1.foo.b.B);
+}
+`))
+		Expect(shaded[1].Path).To(Equal("v1/foo/b/b.proto"))
+		Expect(string(shaded[1].Content)).To(ContainSubstring("package v1.foo.b;\n"))
+
+		// the shaded copy can be compiled alongside the original
+		for _, file := range shaded {
+			writeFile(filepath.Join(root, file.Path), string(file.Content))
+		}
+		compiler := protocompile.Compiler{
+			Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+				ImportPaths: []string{root, includeRoot},
+			}),
+		}
+		_, err = compiler.Compile(context.Background(), "foo/a/a.proto", "v1/foo/a/a.proto")
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("shades the extension names of options", func() {
+		writeFile(filepath.Join(root, "foo/opts/opts.proto"), `syntax = "proto3";
+package foo.opts;
+
+import "google/protobuf/descriptor.proto";
+import "foo/b/b.proto";
+
+option (foo.opts.owner) = "opts";
+
+extend google.protobuf.FileOptions {
+  string owner = 50000;
+}
+
+message Opts {
+  string name = 1 [(opts.field_owner) = "name"];
+}
+
+extend google.protobuf.FieldOptions {
+  string field_owner = 50000;
+}
+`)
+		shader := protoutils.Shader{PackagePrefix: "v1", PathPrefix: "v1"}
+		shaded, err := shader.Shade(context.Background(), root, []string{"foo/opts/opts.proto"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(shaded[0].Content)).To(ContainSubstring(`option (.v1.foo.opts.owner) = "opts";`))
+		Expect(string(shaded[0].Content)).To(ContainSubstring(`string name = 1 [(.v1.foo.opts.field_owner) = "name"];`))
+		Expect(string(shaded[0].Content)).To(ContainSubstring("extend .google.protobuf.FileOptions {"))
+		// b.proto is imported but not shaded
+		Expect(string(shaded[0].Content)).To(ContainSubstring(`import "foo/b/b.proto";`))
+	})
+	It("lists the extensions of messages which are not shaded, and finds the files they conflict with", func() {
+		writeFile(filepath.Join(root, "foo/opts/opts.proto"), `syntax = "proto3";
+package foo.opts;
+
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.FieldOptions {
+  string owner = 50000;
+}
+
+message Opts {
+  extend google.protobuf.MessageOptions {
+    string kind = 50001;
+  }
+}
+`)
+		shader := protoutils.Shader{PackagePrefix: "v1", PathPrefix: "v1"}
+		shaded, err := shader.Shade(context.Background(), root, []string{"foo/opts/opts.proto"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shaded[0].Extensions).To(Equal([]protoutils.Extension{
+			{File: "v1/foo/opts/opts.proto", Name: "v1.foo.opts.owner", Extendee: "google.protobuf.FieldOptions", Number: 50000},
+			{File: "v1/foo/opts/opts.proto", Name: "v1.foo.opts.Opts.kind", Extendee: "google.protobuf.MessageOptions", Number: 50001},
+		}))
+
+		// the original file, vendored alongside the shaded one
+		original := []byte(`syntax = "proto3";
+package foo.opts;
+import "google/protobuf/descriptor.proto";
+extend .google.protobuf.FieldOptions {
+  string owner = 50000;
+  string other = 50002;
+}
+extend google.protobuf.MessageOptions {
+  string kind = 50003;
+}
+`)
+		conflicts, err := protoutils.FindExtensionConflicts(shaded[0].Extensions, "foo/opts/opts.proto", original)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(Equal([]protoutils.ExtensionConflict{
+			{Extension: shaded[0].Extensions[0], File: "foo/opts/opts.proto", Name: "owner"},
+		}))
+		err = &protoutils.ExtensionConflictsError{Conflicts: conflicts}
+		Expect(err.Error()).To(ContainSubstring("v1/foo/opts/opts.proto: v1.foo.opts.owner extends " +
+			"google.protobuf.FieldOptions with number 50000, which foo/opts/opts.proto already uses for owner"))
+	})
+	It("fails when the files do not compile", func() {
+		Expect(os.Remove(filepath.Join(includeRoot, "ext/ext.proto"))).NotTo(HaveOccurred())
+		_, err := protoutils.Shader{PackagePrefix: "v1"}.Shade(context.Background(), root, []string{"foo/a/a.proto"})
+		Expect(err).To(HaveOccurred())
+	})
+})
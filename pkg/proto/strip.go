@@ -0,0 +1,253 @@
+package proto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile/ast"
+)
+
+/*
+ImportResolver returns the contents of the file imported by a proto file, as written in its import statement, and
+false when the import cannot be found.
+*/
+type ImportResolver func(importPath string) ([]byte, bool)
+
+// DirResolver resolves imports against the directories, in order.
+func DirResolver(dirs ...string) ImportResolver {
+	return func(importPath string) ([]byte, bool) {
+		for _, dir := range dirs {
+			if b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(importPath))); err == nil {
+				return b, true
+			}
+		}
+		return nil, false
+	}
+}
+
+/*
+OptionStripper removes options from proto files: the named file options, such as go_package, and every option, at
+any level, which sets an extension from one of the named proto packages, such as (validate.rules) or
+(gogoproto.nullable). Imports which are no longer referenced once the options are gone are removed too, so the
+files no longer depend on the annotation protos. The package of an import is read from the imported file, found
+with Imports, and imports which cannot be resolved are always kept.
+
+OptionStripper implements transform.Transformer, so it can be run on files as they are vendored.
+*/
+type OptionStripper struct {
+	// file options which are removed, e.g. go_package or (gogoproto.goproto_getters_all)
+	Options []string
+	// proto packages whose extensions are removed, e.g. validate or gogoproto
+	Packages []string
+	// resolves the files imported by the stripped files, no imports are removed when it is nil
+	Imports ImportResolver
+}
+
+// StripFile strips the options from the proto file at path, which is left untouched when nothing was stripped.
+func (s OptionStripper) StripFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	stripped, err := s.Strip(path, b)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(b, stripped) {
+		return nil
+	}
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, stripped, fileInfo.Mode())
+}
+
+// Transform strips the options from proto files, other files are returned untouched.
+func (s OptionStripper) Transform(filePath string, content []byte) (string, []byte, error) {
+	if path.Ext(filePath) != ".proto" {
+		return filePath, content, nil
+	}
+	stripped, err := s.Strip(filePath, content)
+	if err != nil {
+		return "", nil, err
+	}
+	return filePath, stripped, nil
+}
+
+/*
+Strip removes the options from the contents of a proto file, and returns the result.
+The filename is only used for error reporting.
+*/
+func (s OptionStripper) Strip(filename string, content []byte) ([]byte, error) {
+	file, err := parseProto(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	before, err := identifiers(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		edits   []edit
+		compact = map[*ast.OptionNode]bool{}
+	)
+	err = ast.Walk(file, ast.NoOpVisitor{}, ast.WithBefore(func(node ast.Node) error {
+		switch n := node.(type) {
+		case *ast.CompactOptionsNode:
+			for _, opt := range n.Options {
+				compact[opt] = true
+			}
+			edits = append(edits, s.stripCompactOptions(file, content, n)...)
+		case *ast.OptionNode:
+			if !compact[n] && s.stripped(n) {
+				start, end := span(file, n)
+				edits = append(edits, removal(content, start, end))
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	stripped := applyEdits(content, edits)
+	if len(s.Options) > 0 {
+		options := make([]FileOption, 0, len(s.Options))
+		for _, name := range s.Options {
+			options = append(options, RemoveOption(name))
+		}
+		if stripped, err = ApplyFileOptions(filename, stripped, options...); err != nil {
+			return nil, err
+		}
+	}
+	if bytes.Equal(content, stripped) || s.Imports == nil {
+		return stripped, nil
+	}
+
+	// drop the imports which were referenced before the options were stripped, but no longer are
+	file, err = parseProto(filename, stripped)
+	if err != nil {
+		return nil, err
+	}
+	after, err := identifiers(file)
+	if err != nil {
+		return nil, err
+	}
+	edits = nil
+	for _, decl := range file.Decls {
+		imp, ok := decl.(*ast.ImportNode)
+		if !ok || imp.Public != nil {
+			continue
+		}
+		pkg, ok := s.importedPackage(imp.Name.AsString())
+		if !ok || !referenced(pkg, before) || referenced(pkg, after) {
+			continue
+		}
+		start, end := span(file, imp)
+		edits = append(edits, removal(stripped, start, end))
+	}
+	return applyEdits(stripped, edits), nil
+}
+
+// returns every identifier in the file, such as type names and the names of extensions set by options
+func identifiers(file *ast.FileNode) ([]string, error) {
+	var identifiers []string
+	err := ast.Walk(file, ast.NoOpVisitor{}, ast.WithBefore(func(node ast.Node) error {
+		if ident, ok := node.(ast.IdentValueNode); ok {
+			identifiers = append(identifiers, strings.TrimPrefix(string(ident.AsIdentifier()), "."))
+		}
+		return nil
+	}))
+	return identifiers, err
+}
+
+// remove the stripped options from a compact [ ... ] list, or the whole list if none remain
+func (s OptionStripper) stripCompactOptions(file *ast.FileNode, content []byte, options *ast.CompactOptionsNode) []edit {
+	removed := make([]bool, len(options.Options))
+	remaining := 0
+	for i, opt := range options.Options {
+		removed[i] = s.stripped(opt)
+		if !removed[i] {
+			remaining++
+		}
+	}
+	if remaining == len(options.Options) {
+		return nil
+	}
+	if remaining == 0 {
+		start, end := span(file, options)
+		for start > 0 && (isBlank(content[start-1]) || content[start-1] == '\n') {
+			start--
+		}
+		return []edit{{start: start, end: end}}
+	}
+
+	var edits []edit
+	// options followed by another option are removed up to the start of the next one, along with their comma
+	last := len(options.Options) - 1
+	for last >= 0 && removed[last] {
+		last--
+	}
+	for i := 0; i < last; i++ {
+		if removed[i] {
+			start, _ := span(file, options.Options[i])
+			next, _ := span(file, options.Options[i+1])
+			edits = append(edits, edit{start: start, end: next})
+		}
+	}
+	// the trailing options are removed from the end of the last remaining option, including the comma before them
+	if last < len(options.Options)-1 {
+		_, start := span(file, options.Options[last])
+		_, end := span(file, options.Options[len(options.Options)-1])
+		edits = append(edits, edit{start: start, end: end})
+	}
+	return edits
+}
+
+// returns true if the option sets an extension from one of the stripped packages
+func (s OptionStripper) stripped(opt *ast.OptionNode) bool {
+	for _, part := range opt.Name.Parts {
+		if !part.IsExtension() {
+			continue
+		}
+		name := strings.TrimPrefix(string(part.Name.AsIdentifier()), ".")
+		for _, pkg := range s.Packages {
+			if strings.HasPrefix(name, pkg+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// returns the package declared by the imported file, if it can be resolved and declares one
+func (s OptionStripper) importedPackage(importPath string) (string, bool) {
+	content, ok := s.Imports(importPath)
+	if !ok {
+		return "", false
+	}
+	file, err := parseProto(importPath, content)
+	if err != nil {
+		return "", false
+	}
+	pkg := packageNode(file)
+	if pkg == nil {
+		return "", false
+	}
+	return string(pkg.Name.AsIdentifier()), true
+}
+
+// returns true if any of the identifiers refer to a name in the package
+func referenced(pkg string, identifiers []string) bool {
+	for _, ident := range identifiers {
+		if strings.HasPrefix(ident, pkg+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package proto_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("OptionStripper", func() {
+	const annotated = `syntax = "proto3";
+package foo;
+
+import "validate/validate.proto";
+import "github.com/gogo/protobuf/gogoproto/gogo.proto";
+import "udpa/annotations/status.proto";
+
+option (gogoproto.equal_all) = true;
+option (udpa.annotations.file_status).package_version_status = ACTIVE;
+option go_package = "github.com/foo/foo";
+
+message Foo {
+  option (validate.disabled) = false;
+
+  string name = 1 [(validate.rules).string = {min_len: 1}];
+  string id = 2 [
+    (validate.rules).string = {min_len: 1},
+    (gogoproto.nullable) = false,
+    deprecated = true
+  ];
+  repeated string tags = 3 [json_name = "tags", (gogoproto.nullable) = false];
+}
+`
+	packages := map[string]string{
+		"validate/validate.proto":                       "validate",
+		"github.com/gogo/protobuf/gogoproto/gogo.proto": "gogoproto",
+		"udpa/annotations/status.proto":                 "udpa.annotations",
+	}
+	imports := func(importPath string) ([]byte, bool) {
+		pkg, ok := packages[importPath]
+		return []byte("syntax = \"proto3\";\npackage " + pkg + ";\n"), ok
+	}
+	stripper := protoutils.OptionStripper{Packages: []string{"validate", "gogoproto"}, Imports: imports}
+
+	It("removes the options and imports of the stripped packages", func() {
+		out, err := stripper.Strip("foo.proto", []byte(annotated))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal(`syntax = "proto3";
+package foo;
+
+import "udpa/annotations/status.proto";
+
+option (udpa.annotations.file_status).package_version_status = ACTIVE;
+option go_package = "github.com/foo/foo";
+
+message Foo {
+
+  string name = 1;
+  string id = 2 [
+    deprecated = true
+  ];
+  repeated string tags = 3 [json_name = "tags"];
+}
+`))
+	})
+	It("keeps imports which are still referenced", func() {
+		content := `syntax = "proto3";
+package foo;
+
+import "validate/validate.proto";
+
+message Foo {
+  string name = 1 [(validate.rules).string = {min_len: 1}];
+  validate.FieldRules rules = 2;
+}
+`
+		out, err := stripper.Strip("foo.proto", []byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(ContainSubstring(`import "validate/validate.proto";`))
+		Expect(string(out)).To(ContainSubstring("  string name = 1;\n"))
+	})
+	It("reads the package of imports from the imported files", func() {
+		dir := GinkgoT().TempDir()
+		for importPath, content := range map[string]string{
+			"pgv.proto":            "syntax = \"proto3\";\npackage validate;\n",
+			"validate/other.proto": "syntax = \"proto3\";\npackage other;\n",
+		} {
+			Expect(os.MkdirAll(filepath.Dir(filepath.Join(dir, importPath)), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, importPath), []byte(content), 0644)).To(Succeed())
+		}
+		stripper := protoutils.OptionStripper{Packages: []string{"validate"}, Imports: protoutils.DirResolver(dir)}
+		content := `syntax = "proto3";
+package foo;
+import "pgv.proto";
+import "validate/other.proto";
+import "validate/validate.proto";
+message Foo {
+  string name = 1 [(validate.rules).string = {min_len: 1}];
+  other.Other other = 2;
+}
+`
+		out, err := stripper.Strip("foo.proto", []byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).NotTo(ContainSubstring("pgv.proto"))
+		Expect(string(out)).To(ContainSubstring(`import "validate/other.proto";`))
+		// imports which cannot be resolved are kept
+		Expect(string(out)).To(ContainSubstring(`import "validate/validate.proto";`))
+	})
+	It("removes named file options and the imports they used", func() {
+		stripper := protoutils.OptionStripper{
+			Options: []string{"(gogoproto.goproto_getters_all)", "go_package"},
+			Imports: imports,
+		}
+		content := `syntax = "proto3";
+package foo;
+import "github.com/gogo/protobuf/gogoproto/gogo.proto";
+import "validate/validate.proto";
+option (gogoproto.goproto_getters_all) = false;
+option go_package = "github.com/foo/foo";
+`
+		out, err := stripper.Strip("foo.proto", []byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal(`syntax = "proto3";
+package foo;
+import "validate/validate.proto";
+`))
+	})
+	It("leaves files without stripped options untouched", func() {
+		content := "syntax = \"proto3\";\nimport \"validate/validate.proto\";\n"
+		out, err := stripper.Strip("foo.proto", []byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal(content))
+
+		path := filepath.Join(GinkgoT().TempDir(), "foo.proto")
+		writeFile(path, content)
+		modified := time.Now().Add(-time.Hour).Truncate(time.Second)
+		Expect(os.Chtimes(path, modified, modified)).To(Succeed())
+		Expect(stripper.StripFile(path)).To(Succeed())
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.ModTime()).To(BeTemporally("==", modified))
+	})
+})
@@ -0,0 +1,66 @@
+package proto
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+/*
+ValidateImports checks that every import of every proto file in files resolves to exactly one file
+in the include roots, the same way protoc would search its -I paths. Well known imports are assumed
+to be provided by protoc.
+
+Imports which match no file, or more than one file, are returned in an *UnresolvedImportsError.
+*/
+func ValidateImports(files []string, includeRoots []string) error {
+	var unresolved []UnresolvedImport
+	for _, file := range files {
+		if filepath.Ext(file) != ".proto" {
+			continue
+		}
+		imports, err := ParseImports(file)
+		if err != nil {
+			return err
+		}
+		for _, imp := range imports {
+			if IsWellKnownImport(imp.Path) {
+				continue
+			}
+			matches := resolveInRoots(imp.Path, includeRoots)
+			if len(matches) == 1 {
+				continue
+			}
+			unresolvedImport := UnresolvedImport{File: file, Line: imp.Line, Import: imp.Path}
+			if len(matches) > 1 {
+				unresolvedImport.Candidates = matches
+			}
+			unresolved = append(unresolved, unresolvedImport)
+		}
+	}
+	if len(unresolved) > 0 {
+		return &UnresolvedImportsError{Imports: unresolved}
+	}
+	return nil
+}
+
+// returns every distinct file the import path resolves to
+func resolveInRoots(importPath string, includeRoots []string) []string {
+	var (
+		matches []string
+		seen    = map[string]bool{}
+	)
+	for _, root := range includeRoots {
+		path, ok := ImportSource{Dir: root}.Resolve(afero.NewOsFs(), importPath)
+		if !ok {
+			continue
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		matches = append(matches, path)
+	}
+	return matches
+}
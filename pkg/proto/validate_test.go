@@ -0,0 +1,72 @@
+package proto_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+var _ = Describe("ValidateImports", func() {
+	var (
+		tmpDir  string
+		apiFile string
+		roots   []string
+	)
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+		roots = []string{
+			tmpDir,
+			filepath.Join(tmpDir, "github.com", "solo-io", "api"),
+		}
+		apiFile = filepath.Join(tmpDir, "github.com", "solo-io", "api", "v1", "api.proto")
+		writeFile(apiFile, `syntax = "proto3";
+package api.v1;
+
+import "google/protobuf/struct.proto";
+import "v1/types.proto";
+import "github.com/solo-io/api/v1/types.proto";
+`)
+		writeFile(filepath.Join(tmpDir, "github.com", "solo-io", "api", "v1", "types.proto"), `syntax = "proto3";
+package api.v1;
+`)
+	})
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("passes when every import resolves to a single file", func() {
+		Expect(protoutils.ValidateImports([]string{apiFile}, roots)).NotTo(HaveOccurred())
+	})
+	It("reports imports which do not resolve", func() {
+		err := protoutils.ValidateImports([]string{apiFile}, roots[:1])
+		Expect(err).To(HaveOccurred())
+		var unresolvedErr *protoutils.UnresolvedImportsError
+		Expect(errors.As(err, &unresolvedErr)).To(BeTrue())
+		Expect(unresolvedErr.Imports).To(ConsistOf(protoutils.UnresolvedImport{
+			File:   apiFile,
+			Line:   5,
+			Import: "v1/types.proto",
+		}))
+	})
+	It("reports imports which resolve to more than one file", func() {
+		otherRoot := filepath.Join(tmpDir, "other")
+		writeFile(filepath.Join(otherRoot, "v1", "types.proto"), `syntax = "proto3";`)
+		err := protoutils.ValidateImports([]string{apiFile}, append(roots, otherRoot))
+		Expect(err).To(HaveOccurred())
+		var unresolvedErr *protoutils.UnresolvedImportsError
+		Expect(errors.As(err, &unresolvedErr)).To(BeTrue())
+		Expect(unresolvedErr.Imports).To(HaveLen(1))
+		Expect(unresolvedErr.Imports[0].Import).To(Equal("v1/types.proto"))
+		Expect(unresolvedErr.Imports[0].Candidates).To(ConsistOf(
+			filepath.Join(tmpDir, "github.com", "solo-io", "api", "v1", "types.proto"),
+			filepath.Join(otherRoot, "v1", "types.proto"),
+		))
+	})
+})
@@ -0,0 +1,96 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/module"
+)
+
+// MetadataFile is the name of the metadata file written to the vendored root of every source.
+const MetadataFile = ".anyvendor.json"
+
+// the kinds of source files are vendored from
+const (
+	SourceTypeGoMod = "gomod"
+	SourceTypeLocal = "local"
+	SourceTypeGit   = "git"
+)
+
+// Metadata describes a source, and the files vendored from it, for tools inspecting the vendor dir.
+type Metadata struct {
+	// one of the SourceType constants
+	Type string `json:"type"`
+	// the module path, or repository URL
+	Source string `json:"source"`
+	// the module version, or git tag or commit
+	Version string `json:"version,omitempty"`
+	// the commit the files were vendored from, when it is known
+	Commit string `json:"commit,omitempty"`
+	// the patterns matching the vendored files
+	Patterns  []string `json:"patterns,omitempty"`
+	Anyvendor string   `json:"anyvendor"`
+	Files     []File   `json:"files"`
+}
+
+// File is a file vendored from a source.
+type File struct {
+	// the path of the file relative to the root of its source
+	Path string `json:"path"`
+	// the path of the vendored file relative to the vendor dir, which may differ when it has been moved
+	Vendored string `json:"vendored"`
+	// the hex encoded sha256 of the vendored file
+	SHA256 string `json:"sha256"`
+}
+
+/*
+ModuleCommit returns the commit abbreviated in the pseudo-version of a go module, or an empty string if the
+version is not a pseudo-version.
+*/
+func ModuleCommit(version string) string {
+	rev, err := module.PseudoVersionRev(version)
+	if err != nil {
+		return ""
+	}
+	return rev
+}
+
+// HashFile returns the hex encoded sha256 of the file.
+func HashFile(fs afero.Fs, path string) (string, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// WriteMetadata writes the metadata to the MetadataFile in dir, with its files sorted by path.
+func WriteMetadata(fs afero.Fs, dir string, metadata Metadata) error {
+	sort.SliceStable(metadata.Files, func(i, j int) bool {
+		return metadata.Files[i].Path < metadata.Files[j].Path
+	})
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, filepath.Join(dir, MetadataFile), append(content, '\n'), 0644)
+}
+
+// ReadMetadata reads the MetadataFile in dir.
+func ReadMetadata(fs afero.Fs, dir string) (Metadata, error) {
+	var metadata Metadata
+	content, err := afero.ReadFile(fs, filepath.Join(dir, MetadataFile))
+	if err != nil {
+		return metadata, err
+	}
+	err = json.Unmarshal(content, &metadata)
+	return metadata, err
+}
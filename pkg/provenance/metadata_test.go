@@ -0,0 +1,40 @@
+package provenance_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Metadata", func() {
+	It("reads the commit from pseudo-versions", func() {
+		Expect(provenance.ModuleCommit("v0.0.0-20210508222113-6edffad5e616")).To(Equal("6edffad5e616"))
+		Expect(provenance.ModuleCommit("v0.6.1")).To(BeEmpty())
+	})
+	It("writes the metadata with its files sorted", func() {
+		fs := afero.NewMemMapFs()
+		Expect(afero.WriteFile(fs, "/vendor_any/github.com/foo/bar/b.proto", []byte("b"), 0644)).To(Succeed())
+		hash, err := provenance.HashFile(fs, "/vendor_any/github.com/foo/bar/b.proto")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(Equal("3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"))
+
+		metadata := provenance.Metadata{
+			Type:     provenance.SourceTypeGoMod,
+			Source:   "github.com/foo/bar",
+			Version:  "v1.0.0",
+			Patterns: []string{"**/*.proto"},
+			Files: []provenance.File{
+				{Path: "b.proto", Vendored: "github.com/foo/bar/b.proto", SHA256: hash},
+				{Path: "a.proto", Vendored: "github.com/foo/bar/a.proto", SHA256: hash},
+			},
+		}
+		Expect(provenance.WriteMetadata(fs, "/vendor_any/github.com/foo/bar", metadata)).To(Succeed())
+		read, err := provenance.ReadMetadata(fs, "/vendor_any/github.com/foo/bar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read.Source).To(Equal("github.com/foo/bar"))
+		Expect(read.Files).To(HaveLen(2))
+		Expect(read.Files[0].Path).To(Equal("a.proto"))
+		Expect(read.Files[1].Path).To(Equal("b.proto"))
+	})
+})
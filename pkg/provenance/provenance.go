@@ -0,0 +1,123 @@
+package provenance
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// SidecarSuffix is appended to the path of files which cannot contain comments, such as json, to name the
+// sidecar file holding their header.
+const SidecarSuffix = ".provenance.json"
+
+const (
+	// the first line of every header, used to recognise the header when it is stripped
+	marker     = "Vendored by anyvendor. DO NOT EDIT."
+	modulePath = "github.com/solo-io/anyvendor"
+)
+
+// Header records where a vendored file came from.
+type Header struct {
+	// the module path, or repository URL, the file was vendored from
+	Source string `json:"source"`
+	// the module version, or the git tag or commit
+	Version string `json:"version,omitempty"`
+	// the path of the file relative to the root of its source
+	Path string `json:"path"`
+	// the version of anyvendor which vendored the file
+	Anyvendor string `json:"anyvendor"`
+}
+
+// Version returns the version of anyvendor compiled into the running binary.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// returns the prefix of a line comment in the file, or false if the file type does not support comments
+func commentPrefix(path string) (string, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".proto":
+		return "//", true
+	case ".yaml", ".yml":
+		return "#", true
+	}
+	return "", false
+}
+
+// NeedsSidecar returns true if the header of the file is written to a sidecar file, rather than the file itself.
+func NeedsSidecar(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".json"
+}
+
+func (h Header) lines() []string {
+	lines := []string{marker, "source: " + h.Source}
+	if h.Version != "" {
+		lines = append(lines, "version: "+h.Version)
+	}
+	return append(lines, "path: "+h.Path, "anyvendor: "+h.Anyvendor)
+}
+
+/*
+Stamp prepends the header, as comments, to the contents of the file at path, replacing any header it already has.
+Files which do not support comments are returned unchanged.
+*/
+func Stamp(path string, content []byte, header Header) []byte {
+	prefix, ok := commentPrefix(path)
+	if !ok {
+		return content
+	}
+	var stamped bytes.Buffer
+	for _, line := range header.lines() {
+		stamped.WriteString(prefix + " " + line + "\n")
+	}
+	stamped.WriteString("\n")
+	stamped.Write(Strip(path, content))
+	return stamped.Bytes()
+}
+
+// Strip removes the header stamped into the contents of the file at path, if it has one.
+func Strip(path string, content []byte) []byte {
+	prefix, ok := commentPrefix(path)
+	if !ok || !bytes.HasPrefix(content, []byte(prefix+" "+marker)) {
+		return content
+	}
+	rest := content
+	for bytes.HasPrefix(rest, []byte(prefix)) {
+		end := bytes.IndexByte(rest, '\n')
+		if end < 0 {
+			return nil
+		}
+		rest = rest[end+1:]
+	}
+	// the blank line separating the header from the contents
+	if bytes.HasPrefix(rest, []byte("\n")) {
+		rest = rest[1:]
+	}
+	return rest
+}
+
+// Sidecar returns the contents of the sidecar file holding the header.
+func Sidecar(header Header) ([]byte, error) {
+	content, err := json.MarshalIndent(header, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
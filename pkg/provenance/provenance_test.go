@@ -0,0 +1,59 @@
+package provenance_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/pkg/provenance"
+)
+
+var _ = Describe("Provenance", func() {
+	header := provenance.Header{
+		Source:    "github.com/envoyproxy/protoc-gen-validate",
+		Version:   "v0.6.1",
+		Path:      "validate/validate.proto",
+		Anyvendor: "v0.3.0",
+	}
+	proto := []byte("// the validate protos\nsyntax = \"proto2\";\n")
+
+	It("stamps the header with the comment syntax of the file", func() {
+		Expect(string(provenance.Stamp("validate.proto", proto, header))).To(Equal(`// Vendored by anyvendor. DO NOT EDIT.
+// source: github.com/envoyproxy/protoc-gen-validate
+// version: v0.6.1
+// path: validate/validate.proto
+// anyvendor: v0.3.0
+
+// the validate protos
+syntax = "proto2";
+`))
+		Expect(string(provenance.Stamp("crd.yaml", []byte("kind: Foo\n"), header))).
+			To(HavePrefix("# Vendored by anyvendor. DO NOT EDIT.\n# source: "))
+		Expect(string(provenance.Stamp("crd.yml", []byte("kind: Foo\n"), header))).
+			To(HavePrefix("# Vendored by anyvendor. DO NOT EDIT.\n# source: "))
+		Expect(provenance.Stamp("LICENSE", []byte("MIT"), header)).To(Equal([]byte("MIT")))
+		Expect(provenance.Stamp("validate.pb.go", []byte("package validate\n"), header)).To(Equal([]byte("package validate\n")))
+	})
+	It("replaces an existing header, and strips it back to the original contents", func() {
+		stamped := provenance.Stamp("validate.proto", proto, header)
+		updated := header
+		updated.Version = "v0.6.2"
+		restamped := provenance.Stamp("validate.proto", stamped, updated)
+		Expect(string(restamped)).To(ContainSubstring("// version: v0.6.2\n"))
+		Expect(string(restamped)).NotTo(ContainSubstring("v0.6.1"))
+		Expect(provenance.Strip("validate.proto", restamped)).To(Equal(proto))
+		Expect(provenance.Strip("validate.proto", proto)).To(Equal(proto))
+	})
+	It("writes the header of json files to a sidecar", func() {
+		Expect(provenance.NeedsSidecar("schema.json")).To(BeTrue())
+		Expect(provenance.NeedsSidecar("validate.proto")).To(BeFalse())
+		content, err := provenance.Sidecar(header)
+		Expect(err).NotTo(HaveOccurred())
+		var decoded provenance.Header
+		Expect(json.Unmarshal(content, &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(header))
+	})
+	It("reports the version of anyvendor", func() {
+		Expect(provenance.Version()).NotTo(BeEmpty())
+	})
+})
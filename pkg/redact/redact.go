@@ -0,0 +1,70 @@
+/*
+Package redact keeps track of the secret values of the process, such as tokens interpolated into a config from the
+environment, so they can be removed from logs and reports.
+*/
+package redact
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces every secret value redacted from a string.
+const Redacted = "[REDACTED]"
+
+var (
+	mu      sync.RWMutex
+	secrets = map[string]bool{}
+	// replaces the registered secrets, longest first, so a secret containing another is redacted whole
+	replacer = strings.NewReplacer()
+)
+
+// Register marks the values as secret, so they are redacted from every string passed to String. Empty values are
+// ignored.
+func Register(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, value := range values {
+		if value != "" {
+			secrets[value] = true
+		}
+	}
+	sorted := make([]string, 0, len(secrets))
+	for secret := range secrets {
+		sorted = append(sorted, secret)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	pairs := make([]string, 0, 2*len(sorted))
+	for _, secret := range sorted {
+		pairs = append(pairs, secret, Redacted)
+	}
+	replacer = strings.NewReplacer(pairs...)
+}
+
+// Reset forgets every registered secret.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	secrets = map[string]bool{}
+	replacer = strings.NewReplacer()
+}
+
+// String returns s with every registered secret replaced by Redacted.
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return replacer.Replace(s)
+}
+
+// Logf logs the formatted message with the standard logger, with the secrets redacted.
+func Logf(format string, args ...interface{}) {
+	log.Print(String(fmt.Sprintf(format, args...)))
+}
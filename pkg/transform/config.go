@@ -0,0 +1,47 @@
+package transform
+
+import (
+	"regexp"
+
+	"github.com/rotisserie/eris"
+	"github.com/solo-io/anyvendor/anyvendor"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+)
+
+/*
+FromConfig builds the chain of transformers described by the config, in order. The imports of the proto files
+whose options are stripped are resolved with imports, which may be nil.
+*/
+func FromConfig(transforms []*anyvendor.Transform, imports protoutils.ImportResolver) (Chain, error) {
+	var chain Chain
+	for i, cfg := range transforms {
+		var transformer Transformer
+		switch typed := cfg.GetTransformType().(type) {
+		case *anyvendor.Transform_RegexReplace:
+			pattern, err := regexp.Compile(typed.RegexReplace.GetPattern())
+			if err != nil {
+				return nil, eris.Wrapf(err, "invalid pattern in transform %d", i)
+			}
+			if typed.RegexReplace.GetPath() {
+				transformer = RegexRename(pattern, typed.RegexReplace.GetReplacement())
+			} else {
+				transformer = RegexReplace(pattern, typed.RegexReplace.GetReplacement())
+			}
+		case *anyvendor.Transform_LineEndings:
+			transformer = NormalizeLineEndings(typed.LineEndings.GetStyle() == anyvendor.LineEndings_CRLF)
+		case *anyvendor.Transform_StripProtoOptions:
+			if len(typed.StripProtoOptions.GetNames()) == 0 && len(typed.StripProtoOptions.GetPackages()) == 0 {
+				return nil, eris.Errorf("transform %d strips no proto options", i)
+			}
+			transformer = protoutils.OptionStripper{
+				Options:  typed.StripProtoOptions.GetNames(),
+				Packages: typed.StripProtoOptions.GetPackages(),
+				Imports:  imports,
+			}
+		default:
+			return nil, eris.Errorf("transform %d has no type", i)
+		}
+		chain = append(chain, ForFiles(cfg.GetPatterns(), transformer))
+	}
+	return chain, nil
+}
@@ -0,0 +1,94 @@
+package transform
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+
+	"github.com/mattn/go-zglob"
+)
+
+/*
+Transformer rewrites a file as it is vendored. It is passed the path of the file, relative to the root it is
+vendored into, along with its contents, and returns the path and contents to write instead. Paths always use
+forward slashes.
+*/
+type Transformer interface {
+	Transform(path string, content []byte) (string, []byte, error)
+}
+
+// Func adapts an ordinary function into a Transformer.
+type Func func(path string, content []byte) (string, []byte, error)
+
+func (f Func) Transform(path string, content []byte) (string, []byte, error) {
+	return f(path, content)
+}
+
+// Chain runs each of the transformers in order, passing the output of one to the next.
+type Chain []Transformer
+
+func (c Chain) Transform(path string, content []byte) (string, []byte, error) {
+	var err error
+	for _, transformer := range c {
+		path, content, err = transformer.Transform(path, content)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return path, content, nil
+}
+
+/*
+ForFiles only runs the transformer on files whose path matches one of the glob patterns, other files are
+passed through untouched. With no patterns every file is transformed.
+*/
+func ForFiles(patterns []string, transformer Transformer) Transformer {
+	if len(patterns) == 0 {
+		return transformer
+	}
+	return Func(func(filePath string, content []byte) (string, []byte, error) {
+		for _, pattern := range patterns {
+			matched, err := zglob.Match(pattern, filePath)
+			if err != nil {
+				return "", nil, err
+			}
+			if matched {
+				return transformer.Transform(filePath, content)
+			}
+		}
+		return filePath, content, nil
+	})
+}
+
+// RegexReplace replaces every match of the pattern in the contents of the file. The replacement may
+// reference submatches, as in regexp.Regexp.ReplaceAll.
+func RegexReplace(pattern *regexp.Regexp, replacement string) Transformer {
+	return Func(func(filePath string, content []byte) (string, []byte, error) {
+		return filePath, pattern.ReplaceAll(content, []byte(replacement)), nil
+	})
+}
+
+// RegexRename replaces every match of the pattern in the path of the file, which moves the vendored file.
+func RegexRename(pattern *regexp.Regexp, replacement string) Transformer {
+	return Func(func(filePath string, content []byte) (string, []byte, error) {
+		return path.Clean(pattern.ReplaceAllString(filePath, replacement)), content, nil
+	})
+}
+
+/*
+NormalizeLineEndings converts the line endings of text files to \n, or to \r\n when crlf is set.
+Files containing a NUL byte are assumed to be binary, and are left untouched.
+*/
+func NormalizeLineEndings(crlf bool) Transformer {
+	return Func(func(filePath string, content []byte) (string, []byte, error) {
+		if bytes.IndexByte(content, 0) >= 0 {
+			return filePath, content, nil
+		}
+		normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+		normalized = bytes.ReplaceAll(normalized, []byte("\r"), []byte("\n"))
+		if crlf {
+			normalized = bytes.ReplaceAll(normalized, []byte("\n"), []byte("\r\n"))
+		}
+		return filePath, normalized, nil
+	})
+}
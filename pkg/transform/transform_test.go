@@ -0,0 +1,119 @@
+package transform_test
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/anyvendor/anyvendor"
+	protoutils "github.com/solo-io/anyvendor/pkg/proto"
+	"github.com/solo-io/anyvendor/pkg/transform"
+)
+
+const protoFile = `syntax = "proto3";
+package foo;
+option go_package = "github.com/foo/foo";
+option java_package = "io.foo";
+
+message Foo {}
+`
+
+var _ = Describe("Transform", func() {
+	run := func(transformer transform.Transformer, path, content string) (string, string) {
+		path, out, err := transformer.Transform(path, []byte(content))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return path, string(out)
+	}
+
+	It("replaces regex matches in the contents", func() {
+		path, out := run(transform.RegexReplace(regexp.MustCompile(`package (\w+);`), "package ${1}.v1;"), "foo/foo.proto", protoFile)
+		Expect(path).To(Equal("foo/foo.proto"))
+		Expect(out).To(ContainSubstring("package foo.v1;\n"))
+	})
+	It("replaces regex matches in the path", func() {
+		path, out := run(transform.RegexRename(regexp.MustCompile(`^foo/`), "bar/"), "foo/foo.proto", protoFile)
+		Expect(path).To(Equal("bar/foo.proto"))
+		Expect(out).To(Equal(protoFile))
+	})
+	It("normalizes line endings", func() {
+		_, out := run(transform.NormalizeLineEndings(false), "a.txt", "a\r\nb\rc\n")
+		Expect(out).To(Equal("a\nb\nc\n"))
+		_, out = run(transform.NormalizeLineEndings(true), "a.txt", "a\r\nb\n")
+		Expect(out).To(Equal("a\r\nb\r\n"))
+		_, out = run(transform.NormalizeLineEndings(false), "a.bin", "a\r\n\x00")
+		Expect(out).To(Equal("a\r\n\x00"))
+	})
+	It("strips proto options", func() {
+		stripper := protoutils.OptionStripper{Options: []string{"go_package"}}
+		_, out := run(stripper, "foo/foo.proto", protoFile)
+		Expect(out).NotTo(ContainSubstring("go_package"))
+		Expect(out).To(ContainSubstring(`option java_package = "io.foo";`))
+
+		_, out = run(stripper, "foo/README.md", "option go_package = 1;")
+		Expect(out).To(Equal("option go_package = 1;"))
+	})
+	It("only transforms matching files", func() {
+		transformer := transform.ForFiles([]string{"foo/**/*.proto"}, transform.RegexReplace(regexp.MustCompile("foo"), "bar"))
+		_, out := run(transformer, "foo/v1/foo.proto", "foo")
+		Expect(out).To(Equal("bar"))
+		_, out = run(transformer, "baz/foo.proto", "foo")
+		Expect(out).To(Equal("foo"))
+	})
+	It("builds a chain from the config", func() {
+		chain, err := transform.FromConfig([]*anyvendor.Transform{
+			{
+				TransformType: &anyvendor.Transform_StripProtoOptions{
+					StripProtoOptions: &anyvendor.StripProtoOptions{Names: []string{"java_package"}},
+				},
+			},
+			{
+				Patterns: []string{"**/*.proto"},
+				TransformType: &anyvendor.Transform_RegexReplace{
+					RegexReplace: &anyvendor.RegexReplace{Pattern: `\.proto$`, Replacement: "_v1.proto", Path: true},
+				},
+			},
+			{
+				TransformType: &anyvendor.Transform_LineEndings{
+					LineEndings: &anyvendor.LineEndings{Style: anyvendor.LineEndings_CRLF},
+				},
+			},
+		}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		path, out := run(chain, "foo/foo.proto", protoFile)
+		Expect(path).To(Equal("foo/foo_v1.proto"))
+		Expect(out).NotTo(ContainSubstring("java_package"))
+		Expect(out).To(HavePrefix("syntax = \"proto3\";\r\npackage foo;\r\n"))
+	})
+	It("strips extensions from the config", func() {
+		imports := func(importPath string) ([]byte, bool) {
+			return []byte("syntax = \"proto3\";\npackage validate;\n"), importPath == "validate/validate.proto"
+		}
+		chain, err := transform.FromConfig([]*anyvendor.Transform{
+			{
+				TransformType: &anyvendor.Transform_StripProtoOptions{
+					StripProtoOptions: &anyvendor.StripProtoOptions{Packages: []string{"validate"}},
+				},
+			},
+		}, imports)
+		Expect(err).NotTo(HaveOccurred())
+		_, out := run(chain, "foo.proto", "syntax = \"proto3\";\nimport \"validate/validate.proto\";\n"+
+			"message Foo {\n  string name = 1 [(validate.rules).string.min_len = 1];\n}\n")
+		Expect(out).To(Equal("syntax = \"proto3\";\nmessage Foo {\n  string name = 1;\n}\n"))
+	})
+	It("rejects invalid transforms in the config", func() {
+		_, err := transform.FromConfig([]*anyvendor.Transform{
+			{
+				TransformType: &anyvendor.Transform_RegexReplace{
+					RegexReplace: &anyvendor.RegexReplace{Pattern: "("},
+				},
+			},
+		}, nil)
+		Expect(err).To(HaveOccurred())
+		_, err = transform.FromConfig([]*anyvendor.Transform{
+			{
+				TransformType: &anyvendor.Transform_StripProtoOptions{StripProtoOptions: &anyvendor.StripProtoOptions{}},
+			},
+		}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})